@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/aculler/conway-gol/life"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// tileGap is the width in pixels of the border between boards tiled with
+// -boards.
+const tileGap = 4
+
+var (
+	boardCount = flag.Int("boards", 1, "run this many independent boards side by side, each seeded from the next -seed along")
+	boardRules = flag.String("board-rules", "", "comma-separated rules for the boards of -boards, in order and repeating, in place of -rule")
+
+	compareTopology = flag.Bool("compare-topology", false, "run two boards side by side from the same seed, wrapping around as a torus on the left and with bounded edges on the right, in place of -boards and -wrap")
+)
+
+// topologyLabels name the boards of -compare-topology, in order.
+var topologyLabels = []string{"torus", "bounded"}
+
+// numBoards returns how many boards are run: the two of -compare-topology, or
+// else -boards.
+func numBoards() int {
+	if *compareTopology {
+		return len(topologyLabels)
+	}
+	return *boardCount
+}
+
+// makeTiledBoard builds board i of numBoards, with zones of -zonerules,
+// seeding it from the i'th seed after seed. The boards of -compare-topology
+// all start from seed itself, and only the first wraps around.
+func makeTiledBoard(i int, seed int64, pattern [][]bool, r life.Rule, zones []life.Zone) *life.Board {
+	if !*compareTopology {
+		seed += int64(i)
+	}
+	board := makeBoard(*rows, *columns, *threshold, seed, pattern, r)
+	board.Zones = zones
+	if *compareTopology {
+		board.Wrap = i == 0
+	}
+	return board
+}
+
+// parseBoardRules returns the rule for each of the numBoards boards: those
+// listed in -board-rules, repeated as needed, or else r for all of them.
+func parseBoardRules(r life.Rule) ([]life.Rule, error) {
+	rules := make([]life.Rule, numBoards())
+	if *boardRules == "" {
+		for i := range rules {
+			rules[i] = r
+		}
+		return rules, nil
+	}
+
+	var listed []life.Rule
+	for _, s := range strings.Split(*boardRules, ",") {
+		r, err := life.ParseRule(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid -board-rules: %v", err)
+		}
+		listed = append(listed, r)
+	}
+	for i := range rules {
+		rules[i] = listed[i%len(listed)]
+	}
+	return rules, nil
+}
+
+// tileLayout returns how many tiles across and down n boards are laid out in:
+// as close to square as they fit.
+func tileLayout(n int) (across, down int) {
+	across = int(math.Ceil(math.Sqrt(float64(n))))
+	down = (n + across - 1) / across
+	return across, down
+}
+
+// tile returns the part of a width by height area given to board i of n, with
+// x and y its offset from the bottom left. Boards are laid out left to right
+// from the top, with tileGap pixels between them.
+func tile(i, n, width, height int) (x, y, w, h int) {
+	if n == 1 {
+		return 0, 0, width, height
+	}
+
+	across, down := tileLayout(n)
+	w = (width - (across-1)*tileGap) / across
+	h = (height - (down-1)*tileGap) / down
+	column, row := i%across, i/across
+	return column * (w + tileGap), height - (row+1)*h - row*tileGap, w, h
+}
+
+// boardViewport returns where board i of n, a rows by columns grid, is drawn
+// in a width by height area: the grid's part of its tile.
+func boardViewport(i, n, width, height, rows, columns int) (x, y, w, h int) {
+	tx, ty, tw, th := tile(i, n, width, height)
+	x, y, w, h = gridViewport(tw, th, rows, columns)
+	return tx + x, ty + y, w, h
+}
+
+// clearTiles clears n tiled boards in a width by height window to the
+// background color, and the borders between them to the -gridcolor.
+func clearTiles(n, width, height int) {
+	gl.ClearColor(gridLinesColor[0], gridLinesColor[1], gridLinesColor[2], gridLinesColor[3])
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	gl.ClearColor(backgroundColor[0], backgroundColor[1], backgroundColor[2], backgroundColor[3])
+	gl.Enable(gl.SCISSOR_TEST)
+	for i := 0; i < n; i++ {
+		x, y, w, h := tile(i, n, width, height)
+		gl.Scissor(int32(x), int32(y), int32(w), int32(h))
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+	}
+	gl.Disable(gl.SCISSOR_TEST)
+}
+
+// tileAt returns which of n tiled boards the position px, py in a width by
+// height area, from its top left, falls on, or false if it's on a border.
+func tileAt(px, py float64, n, width, height int) (int, bool) {
+	for i := 0; i < n; i++ {
+		x, y, w, h := tile(i, n, width, height)
+		top := height - y - h
+		if px >= float64(x) && px < float64(x+w) && py >= float64(top) && py < float64(top+h) {
+			return i, true
+		}
+	}
+	return 0, false
+}
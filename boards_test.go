@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestCompareTopology(t *testing.T) {
+	defer func(c bool, n, r, cs int, th float64) {
+		*compareTopology, *boardCount, *rows, *columns, *threshold = c, n, r, cs, th
+	}(*compareTopology, *boardCount, *rows, *columns, *threshold)
+	*compareTopology, *boardCount, *rows, *columns, *threshold = true, 4, 16, 12, 0.4
+
+	rules, err := parseBoardRules(conway)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 || numBoards() != 2 {
+		t.Fatalf("-compare-topology runs %d boards with %d rules, want 2 of each", numBoards(), len(rules))
+	}
+
+	torus := makeTiledBoard(0, 225, nil, rules[0], nil)
+	bounded := makeTiledBoard(1, 225, nil, rules[1], nil)
+	if !torus.Wrap || bounded.Wrap {
+		t.Errorf("the boards wrap %t and %t, want only the first to", torus.Wrap, bounded.Wrap)
+	}
+	if !boardsEqual(snapshot(torus.Cells), bounded.Cells) {
+		t.Fatal("the boards start from different cells")
+	}
+
+	// Only the edges differ, so it takes a few generations for the boards
+	// to part ways.
+	for i := 0; i < 20; i++ {
+		torus.Step()
+		bounded.Step()
+	}
+	if boardsEqual(snapshot(torus.Cells), bounded.Cells) {
+		t.Error("the boards are still the same after 20 generations")
+	}
+}
+
+func TestTiledBoardSeeds(t *testing.T) {
+	defer func(c bool, r, cs int, th float64) {
+		*compareTopology, *rows, *columns, *threshold = c, r, cs, th
+	}(*compareTopology, *rows, *columns, *threshold)
+	*compareTopology, *rows, *columns, *threshold = false, 16, 12, 0.4
+
+	// Without -compare-topology each board starts from the next seed
+	// along, and they all wrap as -wrap says.
+	a := makeTiledBoard(1, 225, nil, conway, nil)
+	b := makeBoard(*rows, *columns, *threshold, 226, nil, conway)
+	if !boardsEqual(snapshot(a.Cells), b.Cells) {
+		t.Error("board 1 doesn't start from the seed after the one given")
+	}
+	if a.Wrap != *wrap {
+		t.Errorf("board 1 wraps %t, want %t from -wrap", a.Wrap, *wrap)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	brushName = flag.String("brush", "", "pattern stamped by clicking, one of "+strings.Join(brushNames, ", ")+" (empty toggles single cells)")
+)
+
+// brushNames lists the brushes in the order the number keys pick them, from 1.
+var brushNames = []string{"glider", "lwss", "pulsar", "rpentomino"}
+
+// brushes are the patterns that can be stamped onto the board, indexed [x][y]
+// like the grid.
+var brushes = map[string][][]bool{
+	"glider":     mustParseRLE("x = 3, y = 3\nbo$2bo$3o!"),
+	"lwss":       mustParseRLE("x = 5, y = 4\nbo2bo$o4b$o3bo$4o!"),
+	"pulsar":     mustParseRLE("x = 13, y = 13\n2b3o3b3o2$o4bobo4bo$o4bobo4bo$o4bobo4bo$2b3o3b3o2$2b3o3b3o$o4bobo4bo$o4bobo4bo$o4bobo4bo2$2b3o3b3o!"),
+	"rpentomino": mustParseRLE("x = 3, y = 3\nb2o$2o$bo!"),
+}
+
+// mustParseRLE parses a built in RLE pattern, panicking if it's malformed.
+func mustParseRLE(rle string) [][]bool {
+	pattern, err := parseRLE(strings.NewReader(rle))
+	if err != nil {
+		panic(fmt.Sprintf("bad built in pattern: %v", err))
+	}
+	return pattern
+}
+
+// stampBrush adds the brush's live cells to the board, centered on the cell at
+// x, y, leaving the cells around them as they were. Cells past an edge wrap
+// around when the board wraps and are dropped otherwise.
+func stampBrush(board *life.Board, brush [][]bool, x, y int) {
+	originX := x - len(brush)/2
+	originY := y - len(brush[0])/2
+	if *mode != modeSmooth {
+		board.Place(brush, originX, originY, board.Wrap)
+		return
+	}
+
+	// Smooth cells come alive through their state instead.
+	cells, wrap := board.Cells, board.Wrap
+	rows, columns := len(cells), len(cells[0])
+	for bx := range brush {
+		for by, alive := range brush[bx] {
+			if !alive {
+				continue
+			}
+
+			cx, cy := originX+bx, originY+by
+			if wrap {
+				cx = (cx%rows + rows) % rows
+				cy = (cy%columns + columns) % columns
+			} else if cx < 0 || cx >= rows || cy < 0 || cy >= columns {
+				continue
+			}
+
+			cells[cx][cy].State = 1
+		}
+	}
+}
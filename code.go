@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+// maxCodeSize is the largest number of rows or columns a code can describe,
+// since each is stored in a single byte.
+const maxCodeSize = 255
+
+// encodeBoard packs the board into a short code that can be pasted back in
+// with -code. The code is a two byte header holding the number of rows and
+// columns, followed by one bit per cell row by row, all in URL-safe base64.
+func encodeBoard(cells [][]*life.Cell) (string, error) {
+	rows, columns := len(cells), len(cells[0])
+	if rows > maxCodeSize || columns > maxCodeSize {
+		return "", fmt.Errorf("board is %dx%d, codes can be at most %dx%d", rows, columns, maxCodeSize, maxCodeSize)
+	}
+
+	data := make([]byte, 2+(rows*columns+7)/8)
+	data[0], data[1] = byte(rows), byte(columns)
+
+	bits := data[2:]
+	for x := range cells {
+		for y, c := range cells[x] {
+			if c.Alive() {
+				i := x*columns + y
+				bits[i/8] |= 0x80 >> uint(i%8)
+			}
+		}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeBoard unpacks a code made by encodeBoard into the live cells it
+// describes, indexed the same way as the cell grid.
+func decodeBoard(code string) ([][]bool, error) {
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid code: %v", err)
+	}
+	if len(data) < 2 || data[0] == 0 || data[1] == 0 {
+		return nil, errors.New("invalid code: missing dimensions")
+	}
+
+	rows, columns := int(data[0]), int(data[1])
+	bits := data[2:]
+	if len(bits) != (rows*columns+7)/8 {
+		return nil, fmt.Errorf("invalid code: %dx%d board needs %d bytes, got %d", rows, columns, (rows*columns+7)/8, len(bits))
+	}
+
+	board := make([][]bool, rows)
+	for x := range board {
+		board[x] = make([]bool, columns)
+		for y := range board[x] {
+			i := x*columns + y
+			board[x][y] = bits[i/8]&(0x80>>uint(i%8)) != 0
+		}
+	}
+
+	return board, nil
+}
+
+// applyBoard clears the grid and places board in its center. A board the
+// same size as the grid is reproduced exactly.
+func applyBoard(cells [][]*life.Cell, board [][]bool) error {
+	rows, columns := len(board), len(board[0])
+	if rows > len(cells) || columns > len(cells[0]) {
+		return fmt.Errorf("code is for a %dx%d board, which doesn't fit the %dx%d grid", rows, columns, len(cells), len(cells[0]))
+	}
+
+	for x := range cells {
+		for _, c := range cells[x] {
+			c.Set(false)
+		}
+	}
+
+	offsetX := (len(cells) - rows) / 2
+	offsetY := (len(cells[0]) - columns) / 2
+	for x := range board {
+		for y, alive := range board[x] {
+			cells[x+offsetX][y+offsetY].Set(alive)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ageColors        = flag.Bool("agecolors", false, "shade live cells from -youngcolor to -oldcolor by how long they've been alive")
+	youngColorString = flag.String("youngcolor", "1,0.9,0.3", "R,G,B color of newly born cells with -agecolors, each from 0 to 1")
+	oldColorString   = flag.String("oldcolor", "0.2,0.3,1", "R,G,B color of cells at -maxage with -agecolors, each from 0 to 1")
+	maxAge           = flag.Int("maxage", 50, "generations alive after which -agecolors stops shading a cell")
+
+	colorByNeighbors = flag.Bool("color-by-neighbors", false, "tint live cells by how many live neighbors they have, from blue for few through green to red for crowded")
+
+	solidColorString = flag.String("color", "", "R,G,B color to draw every live cell in, each from 0 to 1, in place of -palette; press K to adjust it with the arrow keys")
+
+	paletteString = flag.String("palette", paletteRandom, "colors given to cells: random, a named palette ("+strings.Join(paletteNames(), ", ")+") or a comma-separated list of hex colors such as ff8800,#2050c0")
+
+	backgroundColorString = flag.String("bg", "0,0,0", "R,G,B or R,G,B,A background color, each from 0 to 1")
+	gridLinesColorString  = flag.String("gridcolor", "0.25,0.25,0.25", "R,G,B color of -gridlines, each from 0 to 1")
+
+	// These are parsed from their flags by parseColorFlags. solidColor is
+	// only used if -color is set.
+	cellPalette     colorPalette
+	solidColor      [4]float32
+	backgroundColor [4]float32
+	youngColor      [4]float32
+	oldColor        [4]float32
+	gridLinesColor  [4]float32
+)
+
+// paletteRandom is the palette of random colors, each channel at least 0.2.
+const paletteRandom = "random"
+
+// palettes are the named palettes -palette accepts besides random.
+var palettes = map[string]colorPalette{
+	"fire": {
+		{0.5, 0.05, 0.05, 1},
+		{0.85, 0.15, 0.05, 1},
+		{1, 0.45, 0.05, 1},
+		{1, 0.75, 0.1, 1},
+		{1, 0.95, 0.6, 1},
+	},
+	"ocean": {
+		{0.05, 0.15, 0.45, 1},
+		{0.1, 0.35, 0.75, 1},
+		{0.1, 0.6, 0.7, 1},
+		{0.3, 0.8, 0.85, 1},
+		{0.6, 0.95, 0.9, 1},
+	},
+	"mono": {
+		{0.55, 0.55, 0.55, 1},
+		{0.75, 0.75, 0.75, 1},
+		{1, 1, 1, 1},
+	},
+}
+
+// colorPalette is a set of colors to give cells. A nil palette gives them
+// random colors instead.
+type colorPalette [][4]float32
+
+// paletteNames returns the names of the named palettes, sorted.
+func paletteNames() []string {
+	var names []string
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parsePalette reads a -palette value: random, the name of a palette or a
+// comma-separated list of colors as RRGGBB hex, each optionally starting
+// with #.
+func parsePalette(s string) (colorPalette, error) {
+	if s == paletteRandom {
+		return nil, nil
+	}
+	if p, ok := palettes[s]; ok {
+		return p, nil
+	}
+
+	var p colorPalette
+	for _, hex := range strings.Split(s, ",") {
+		hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return nil, fmt.Errorf("invalid palette %q: want random, one of %s, or hex colors like ff8800", s, strings.Join(paletteNames(), ", "))
+		}
+		p = append(p, [4]float32{
+			float32(v>>16&0xff) / 255,
+			float32(v>>8&0xff) / 255,
+			float32(v&0xff) / 255,
+			1,
+		})
+	}
+	return p, nil
+}
+
+// sample picks a color for a cell from the palette using r.
+func (p colorPalette) sample(r *rand.Rand) [4]float32 {
+	if p == nil {
+		return randomColor(r)
+	}
+	return p[r.Intn(len(p))]
+}
+
+// teamColors are the colors of the two teams with -immigration.
+var teamColors = [2][4]float32{
+	{1, 0.35, 0.25, 1},
+	{0.25, 0.6, 1, 1},
+}
+
+// parseColorFlags parses every color flag into the color it sets.
+func parseColorFlags() error {
+	flags := []struct {
+		name  string
+		value string
+		color *[4]float32
+	}{
+		{"bg", *backgroundColorString, &backgroundColor},
+		{"youngcolor", *youngColorString, &youngColor},
+		{"oldcolor", *oldColorString, &oldColor},
+		{"gridcolor", *gridLinesColorString, &gridLinesColor},
+	}
+
+	p, err := parsePalette(*paletteString)
+	if err != nil {
+		return fmt.Errorf("-palette: %v", err)
+	}
+	cellPalette = p
+
+	for _, f := range flags {
+		color, err := parseColor(f.value)
+		if err != nil {
+			return fmt.Errorf("-%s: %v", f.name, err)
+		}
+		*f.color = color
+	}
+
+	if *solidColorString != "" {
+		if solidColor, err = parseColor(*solidColorString); err != nil {
+			return fmt.Errorf("-color: %v", err)
+		}
+	}
+	return nil
+}
+
+// parseColor reads a color written as R,G,B or R,G,B,A with each channel
+// from 0 to 1. Alpha defaults to 1.
+func parseColor(s string) ([4]float32, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return [4]float32{}, fmt.Errorf("invalid color %q: want R,G,B or R,G,B,A", s)
+	}
+
+	color := [4]float32{0, 0, 0, 1}
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil || v < 0 || v > 1 {
+			return [4]float32{}, fmt.Errorf("invalid color %q: channels must be numbers from 0 to 1", s)
+		}
+		color[i] = float32(v)
+	}
+	return color, nil
+}
+
+// neighborColors are the colors of live cells with -color-by-neighbors,
+// indexed by their live neighbor count. Counts past the end take the last.
+var neighborColors = [...][4]float32{
+	{0.3, 0.2, 0.8, 1},
+	{0.2, 0.3, 1, 1},
+	{0.2, 0.6, 1, 1},
+	{0.2, 0.9, 0.3, 1},
+	{1, 0.85, 0.2, 1},
+	{1, 0.5, 0.1, 1},
+	{1, 0.15, 0.1, 1},
+}
+
+// neighborColor returns the color of a live cell with n live neighbors.
+func neighborColor(n int) [4]float32 {
+	if n >= len(neighborColors) {
+		n = len(neighborColors) - 1
+	}
+	return neighborColors[n]
+}
+
+// ageColor returns the color of a cell that has been alive for age
+// generations, blending from youngColor to oldColor over maxAge generations.
+func ageColor(age int) [4]float32 {
+	t := float32(1)
+	if age < *maxAge {
+		t = float32(age) / float32(*maxAge)
+	}
+
+	var color [4]float32
+	for i := range color {
+		color[i] = youngColor[i] + (oldColor[i]-youngColor[i])*t
+	}
+	return color
+}
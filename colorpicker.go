@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+const (
+	// pickerHueStep is how far, in degrees, each arrow key press turns the
+	// hue, and pickerStep how far it moves the saturation or value.
+	pickerHueStep = 10
+	pickerStep    = 0.05
+
+	// The swatch of the color being picked sits in the top-left corner, in
+	// normalized device coordinates.
+	swatchLeft   = -0.95
+	swatchRight  = -0.8
+	swatchBottom = 0.8
+	swatchTop    = 0.95
+)
+
+// colorPicker adjusts the -color of live cells from the keyboard while it's
+// active, in hue, saturation and value so each key moves the color in a way
+// that's easy to follow. The color is kept as HSV rather than worked back out
+// of the RGB each time, so the hue isn't lost when the color goes grey.
+type colorPicker struct {
+	active bool
+
+	// hue is in degrees from 0 up to 360, and saturation and value from 0
+	// to 1.
+	hue, saturation, value float64
+
+	swatch    uint32
+	swatchVbo uint32
+}
+
+func newColorPicker() *colorPicker {
+	p := &colorPicker{}
+	p.swatch, p.swatchVbo = makeVao([]float32{
+		swatchLeft, swatchTop, 0,
+		swatchLeft, swatchBottom, 0,
+		swatchRight, swatchBottom, 0,
+
+		swatchLeft, swatchTop, 0,
+		swatchRight, swatchTop, 0,
+		swatchRight, swatchBottom, 0,
+	})
+	return p
+}
+
+// delete frees the picker's GL objects.
+func (p *colorPicker) delete() {
+	gl.DeleteVertexArrays(1, &p.swatch)
+	gl.DeleteBuffers(1, &p.swatchVbo)
+}
+
+// toggle starts picking from the current -color, or stops, keeping the color
+// picked.
+func (p *colorPicker) toggle() {
+	p.active = !p.active
+	if p.active {
+		p.hue, p.saturation, p.value = rgbToHSV(solidColor)
+	}
+}
+
+// key nudges the color for an arrow key while the picker is active: left and
+// right turn the hue, up and down change the value, or the saturation with
+// Shift. It reports whether it used the key.
+func (p *colorPicker) key(key glfw.Key, mods glfw.ModifierKey) bool {
+	if !p.active {
+		return false
+	}
+
+	var dHue, dSaturation, dValue float64
+	switch {
+	case key == glfw.KeyLeft:
+		dHue = -pickerHueStep
+	case key == glfw.KeyRight:
+		dHue = pickerHueStep
+	case key == glfw.KeyUp && mods&glfw.ModShift != 0:
+		dSaturation = pickerStep
+	case key == glfw.KeyDown && mods&glfw.ModShift != 0:
+		dSaturation = -pickerStep
+	case key == glfw.KeyUp:
+		dValue = pickerStep
+	case key == glfw.KeyDown:
+		dValue = -pickerStep
+	default:
+		return false
+	}
+	p.nudge(dHue, dSaturation, dValue)
+	return true
+}
+
+// nudge moves the color by the given amounts, wrapping the hue around and
+// keeping the saturation and value between 0 and 1. The new color becomes
+// the -color, so saving the config keeps it.
+func (p *colorPicker) nudge(dHue, dSaturation, dValue float64) {
+	p.hue = math.Mod(p.hue+dHue+360, 360)
+	p.saturation = math.Max(0, math.Min(1, p.saturation+dSaturation))
+	p.value = math.Max(0, math.Min(1, p.value+dValue))
+
+	solidColor = hsvToRGB(p.hue, p.saturation, p.value)
+	*solidColorString = formatColor(solidColor)
+}
+
+// draw shows the color being picked as a swatch, while the picker is active.
+func (p *colorPicker) draw(colorLocation int32) {
+	if !p.active {
+		return
+	}
+	gl.Uniform4f(colorLocation, solidColor[0], solidColor[1], solidColor[2], 1)
+	gl.BindVertexArray(p.swatch)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+}
+
+// hexColor returns a color as #rrggbb.
+func hexColor(c [4]float32) string {
+	channel := func(v float32) int { return int(math.Round(float64(v) * 255)) }
+	return fmt.Sprintf("#%02x%02x%02x", channel(c[0]), channel(c[1]), channel(c[2]))
+}
+
+// formatColor returns a color as R,G,B the way parseColor reads it.
+func formatColor(c [4]float32) string {
+	return fmt.Sprintf("%.3g,%.3g,%.3g", c[0], c[1], c[2])
+}
+
+// rgbToHSV converts an RGB color to its hue in degrees and its saturation and
+// value from 0 to 1. Greys have a hue of 0.
+func rgbToHSV(c [4]float32) (hue, saturation, value float64) {
+	r, g, b := float64(c[0]), float64(c[1]), float64(c[2])
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	value = max
+	if max == 0 {
+		return 0, 0, 0
+	}
+	saturation = (max - min) / max
+	if max == min {
+		return 0, saturation, value
+	}
+
+	d := max - min
+	switch max {
+	case r:
+		hue = 60 * math.Mod((g-b)/d+6, 6)
+	case g:
+		hue = 60 * ((b-r)/d + 2)
+	default:
+		hue = 60 * ((r-g)/d + 4)
+	}
+	return hue, saturation, value
+}
+
+// hsvToRGB converts a hue in degrees and a saturation and value from 0 to 1 to
+// an opaque RGB color.
+func hsvToRGB(hue, saturation, value float64) [4]float32 {
+	c := value * saturation
+	h := hue / 60
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case h < 1:
+		r, g = c, x
+	case h < 2:
+		r, g = x, c
+	case h < 3:
+		g, b = c, x
+	case h < 4:
+		g, b = x, c
+	case h < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := value - c
+	return [4]float32{float32(r + m), float32(g + m), float32(b + m), 1}
+}
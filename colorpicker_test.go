@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHSVRoundTrip(t *testing.T) {
+	tests := []struct {
+		rgb                    [4]float32
+		hue, saturation, value float64
+	}{
+		{[4]float32{1, 0, 0, 1}, 0, 1, 1},
+		{[4]float32{0, 1, 0, 1}, 120, 1, 1},
+		{[4]float32{0, 0, 1, 1}, 240, 1, 1},
+		{[4]float32{1, 0.5, 0, 1}, 30, 1, 1},
+		{[4]float32{0.5, 0.25, 0.5, 1}, 300, 0.5, 0.5},
+		{[4]float32{0.4, 0.4, 0.4, 1}, 0, 0, 0.4},
+		{[4]float32{0, 0, 0, 1}, 0, 0, 0},
+	}
+
+	const epsilon = 1e-6
+	for _, tt := range tests {
+		h, s, v := rgbToHSV(tt.rgb)
+		if math.Abs(h-tt.hue) > epsilon || math.Abs(s-tt.saturation) > epsilon || math.Abs(v-tt.value) > epsilon {
+			t.Errorf("rgbToHSV(%v) = %g, %g, %g, want %g, %g, %g", tt.rgb, h, s, v, tt.hue, tt.saturation, tt.value)
+		}
+		got := hsvToRGB(tt.hue, tt.saturation, tt.value)
+		for i := range got {
+			if math.Abs(float64(got[i]-tt.rgb[i])) > epsilon {
+				t.Errorf("hsvToRGB(%g, %g, %g) = %v, want %v", tt.hue, tt.saturation, tt.value, got, tt.rgb)
+				break
+			}
+		}
+	}
+}
+
+func TestColorPickerNudge(t *testing.T) {
+	defer func(s string, c [4]float32) {
+		*solidColorString, solidColor = s, c
+	}(*solidColorString, solidColor)
+	*solidColorString, solidColor = "1,0,0", [4]float32{1, 0, 0, 1}
+
+	p := &colorPicker{}
+	p.toggle()
+
+	// The hue wraps around below red to magenta.
+	p.nudge(-60, 0, 0)
+	if p.hue != 300 || solidColor != [4]float32{1, 0, 1, 1} {
+		t.Errorf("turned back 60 degrees from red to hue %g, color %v, want 300 and magenta", p.hue, solidColor)
+	}
+
+	// Saturation and value stop at their ends, and the hue survives the
+	// color going grey.
+	p.nudge(0, -2, -0.5)
+	if p.saturation != 0 || p.value != 0.5 {
+		t.Errorf("saturation %g and value %g, want 0 and 0.5", p.saturation, p.value)
+	}
+	p.nudge(0, 1, 0)
+	if want := hsvToRGB(300, 1, 0.5); solidColor != want {
+		t.Errorf("color %v after bringing the saturation back, want %v", solidColor, want)
+	}
+
+	// The color picked becomes the -color that a saved config holds.
+	c, err := parseColor(*solidColorString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hexColor(c) != hexColor(solidColor) || hexColor(solidColor) != "#800080" {
+		t.Errorf("-color is %q (%s), want the picked %s", *solidColorString, hexColor(c), hexColor(solidColor))
+	}
+	if cfg := currentConfig(); cfg.Color == nil || *cfg.Color != *solidColorString {
+		t.Errorf("saved config has -color %v, want %q", cfg.Color, *solidColorString)
+	}
+}
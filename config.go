@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+var (
+	configFile     = flag.String("config", "", "load settings from a JSON file of flag names and values; flags given on the command line or through GOL_ environment variables win")
+	dumpConfigFile = flag.String("dump-config", "", "save the settings in effect to a JSON file that -config can load")
+)
+
+// Config is every setting a config file can hold: one for each flag other
+// than -config and -dump-config, keyed by the flag's name. A file can leave
+// any of them out, so each is a pointer that's nil when it's missing.
+type Config struct {
+	AgeColors            *bool    `json:"agecolors,omitempty"`
+	Align                *string  `json:"align,omitempty"`
+	Background           *string  `json:"bg,omitempty"`
+	BlobSize             *int     `json:"blobsize,omitempty"`
+	BlobSpacing          *int     `json:"blobspacing,omitempty"`
+	BoardRules           *string  `json:"board-rules,omitempty"`
+	Boards               *int     `json:"boards,omitempty"`
+	BPM                  *float64 `json:"bpm,omitempty"`
+	Brush                *string  `json:"brush,omitempty"`
+	CellSize             *int     `json:"cell-size,omitempty"`
+	Code                 *string  `json:"code,omitempty"`
+	Color                *string  `json:"color,omitempty"`
+	ColorByNeighbors     *bool    `json:"color-by-neighbors,omitempty"`
+	CompareTopology      *bool    `json:"compare-topology,omitempty"`
+	Columns              *int     `json:"columns,omitempty"`
+	CornerWrap           *bool    `json:"cornerwrap,omitempty"`
+	Countdown            *int     `json:"countdown,omitempty"`
+	DetectPeriod         *int     `json:"detect-period,omitempty"`
+	DetectSpaceship      *int     `json:"detect-spaceship,omitempty"`
+	EnergyCost           *float64 `json:"energy-cost,omitempty"`
+	EnergyRegen          *float64 `json:"energy-regen,omitempty"`
+	Events               *string  `json:"events,omitempty"`
+	ExitOnDeath          *bool    `json:"exit-on-death,omitempty"`
+	Fast                 *bool    `json:"fast,omitempty"`
+	FocusActive          *bool    `json:"focusactive,omitempty"`
+	FocusAfter           *int     `json:"focusafter,omitempty"`
+	FPS                  *int     `json:"fps,omitempty"`
+	Frames               *int     `json:"frames,omitempty"`
+	Generations          *int     `json:"generations,omitempty"`
+	Graph                *bool    `json:"graph,omitempty"`
+	GridColor            *string  `json:"gridcolor,omitempty"`
+	GridLines            *bool    `json:"gridlines,omitempty"`
+	Grow                 *bool    `json:"grow,omitempty"`
+	GrowMax              *int     `json:"grow-max,omitempty"`
+	Headless             *bool    `json:"headless,omitempty"`
+	Height               *int     `json:"height,omitempty"`
+	Hex                  *bool    `json:"hex,omitempty"`
+	Hidden               *bool    `json:"hidden,omitempty"`
+	HTTP                 *string  `json:"http,omitempty"`
+	Image                *string  `json:"image,omitempty"`
+	ImageFit             *string  `json:"image-fit,omitempty"`
+	ImageSeedInteractive *bool    `json:"imageseed-interactive,omitempty"`
+	ImageThreshold       *float64 `json:"image-threshold,omitempty"`
+	Immigration          *bool    `json:"immigration,omitempty"`
+	Letterbox            *bool    `json:"letterbox,omitempty"`
+	Manual               *bool    `json:"manual,omitempty"`
+	MaxAge               *int     `json:"maxage,omitempty"`
+	Methuselah           *string  `json:"methuselah,omitempty"`
+	Mode                 *string  `json:"mode,omitempty"`
+	MorphGens            *int     `json:"morphgens,omitempty"`
+	MSAA                 *int     `json:"msaa,omitempty"`
+	Neighborhood         *string  `json:"neighborhood,omitempty"`
+	NoiseScale           *int     `json:"noisescale,omitempty"`
+	OldColor             *string  `json:"oldcolor,omitempty"`
+	Outline              *bool    `json:"outline,omitempty"`
+	OutlineWidth         *float64 `json:"outline-width,omitempty"`
+	Oversized            *string  `json:"oversized,omitempty"`
+	Palette              *string  `json:"palette,omitempty"`
+	Pattern              *string  `json:"pattern,omitempty"`
+	Quiet                *bool    `json:"quiet,omitempty"`
+	Record               *string  `json:"record,omitempty"`
+	Rows                 *int     `json:"rows,omitempty"`
+	Rule                 *string  `json:"rule,omitempty"`
+	RuleFrom             *string  `json:"rulefrom,omitempty"`
+	RuleTo               *string  `json:"ruleto,omitempty"`
+	Seed                 *int64   `json:"seed,omitempty"`
+	SeedStyle            *string  `json:"seedstyle,omitempty"`
+	SmoothAlphaM         *float64 `json:"smooth-alpham,omitempty"`
+	SmoothAlphaN         *float64 `json:"smooth-alphan,omitempty"`
+	SmoothB1             *float64 `json:"smooth-b1,omitempty"`
+	SmoothB2             *float64 `json:"smooth-b2,omitempty"`
+	SmoothD1             *float64 `json:"smooth-d1,omitempty"`
+	SmoothD2             *float64 `json:"smooth-d2,omitempty"`
+	SmoothDt             *float64 `json:"smooth-dt,omitempty"`
+	SmoothRadius         *float64 `json:"smooth-radius,omitempty"`
+	SpacetimeLayers      *int     `json:"spacetime-layers,omitempty"`
+	States               *int     `json:"states,omitempty"`
+	Stats                *string  `json:"stats,omitempty"`
+	Subdivisions         *int     `json:"subdivisions,omitempty"`
+	SVGAnim              *string  `json:"svganim,omitempty"`
+	SVGGens              *int     `json:"svggens,omitempty"`
+	Threshold            *float64 `json:"threshold,omitempty"`
+	Trails               *int     `json:"trails,omitempty"`
+	Twist                *int     `json:"twist,omitempty"`
+	Verbose              *bool    `json:"v,omitempty"`
+	VeryVerbose          *bool    `json:"vv,omitempty"`
+	Warmup               *int     `json:"warmup,omitempty"`
+	WatchFor             *string  `json:"watchfor,omitempty"`
+	Width                *int     `json:"width,omitempty"`
+	WildCards            *string  `json:"wildcards,omitempty"`
+	WildRule             *string  `json:"wildrule,omitempty"`
+	Wrap                 *bool    `json:"wrap,omitempty"`
+	YoungColor           *string  `json:"youngcolor,omitempty"`
+	ZoneRules            *string  `json:"zonerules,omitempty"`
+}
+
+// envSettings are the environment variables that can stand in for the flag
+// named alongside, for deployments where flags are awkward to pass.
+var envSettings = []struct{ env, flag string }{
+	{"GOL_RULE", "rule"},
+	{"GOL_ROWS", "rows"},
+	{"GOL_COLUMNS", "columns"},
+	{"GOL_DENSITY", "threshold"},
+	{"GOL_SEED", "seed"},
+	{"GOL_FPS", "fps"},
+}
+
+// loadSettings sets every flag in fs the command line left out that the
+// environment or the config file at path, if there is one, gives a value.
+// getenv looks up environment variables.
+//
+// Each setting comes from the first of these that has it:
+//
+//  1. the flag given on the command line
+//  2. its environment variable in envSettings, unless that's empty
+//  3. the config file
+//  4. the flag's default
+//
+// A source only sets the flags none before it did, since setting a flag
+// counts as giving it, so the command line always wins.
+func loadSettings(fs *flag.FlagSet, path string, getenv func(string) string) error {
+	for _, s := range envSettings {
+		value := getenv(s.env)
+		if value == "" || isSet(fs, s.flag) {
+			continue
+		}
+		if err := fs.Set(s.flag, value); err != nil {
+			return fmt.Errorf("%s: %v", s.env, err)
+		}
+	}
+
+	if path == "" {
+		return nil
+	}
+	return loadConfig(fs, path)
+}
+
+// readConfig decodes a JSON config file, such as
+// {"rows": 80, "rule": "B36/S23", "wrap": false}. Keys that aren't settings,
+// and values of the wrong type, are errors.
+func readConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d := json.NewDecoder(f)
+	d.DisallowUnknownFields()
+	var c Config
+	if err := d.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &c, nil
+}
+
+// loadConfig sets every flag in fs given a value in a config file that
+// wasn't already set, on the command line or from the environment. The
+// values are checked along with the command line by checkFlags.
+func loadConfig(fs *flag.FlagSet, path string) error {
+	c, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+	return c.apply(fs)
+}
+
+// apply sets the flag in fs for every setting in the config that isn't
+// already set, through the flag's own parsing.
+func (c *Config) apply(fs *flag.FlagSet) error {
+	var err error
+	c.settings(func(name string, v reflect.Value) {
+		if err != nil || v.IsNil() || isSet(fs, name) {
+			return
+		}
+		if setErr := fs.Set(name, fmt.Sprint(v.Elem().Interface())); setErr != nil {
+			err = fmt.Errorf("%q: %v", name, setErr)
+		}
+	})
+	return err
+}
+
+// currentConfig returns the value of every setting. An unset -seed is left
+// out, so loading the config still picks a new seed each run rather than
+// always using zero.
+func currentConfig() *Config {
+	var c Config
+	c.settings(func(name string, v reflect.Value) {
+		if name == "seed" && !isFlagSet("seed") {
+			return
+		}
+		value := reflect.New(v.Type().Elem())
+		value.Elem().Set(reflect.ValueOf(flag.Lookup(name).Value.(flag.Getter).Get()))
+		v.Set(value)
+	})
+	return &c
+}
+
+// settings calls f with the flag name and field of every setting in c.
+func (c *Config) settings(f func(name string, v reflect.Value)) {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		f(name, v.Field(i))
+	}
+}
+
+// saveConfig writes the value of every setting to a JSON file in the format
+// loadConfig reads.
+func saveConfig(path string) error {
+	data, err := json.MarshalIndent(currentConfig(), "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
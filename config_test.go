@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a config file holding data and returns its path.
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigCoversFlags(t *testing.T) {
+	// Every flag but the config ones is a setting, of the flag's own type.
+	fields := map[string]reflect.Type{}
+	new(Config).settings(func(name string, v reflect.Value) {
+		fields[name] = v.Type().Elem()
+	})
+
+	flag.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" || f.Name == "dump-config" || f.Name == "update" || strings.HasPrefix(f.Name, "test.") {
+			return
+		}
+		typ, ok := fields[f.Name]
+		if !ok {
+			t.Errorf("Config has no setting for -%s", f.Name)
+			return
+		}
+		if want := reflect.TypeOf(f.Value.(flag.Getter).Get()); typ != want {
+			t.Errorf("Config's -%s is a %v, want %v", f.Name, typ, want)
+		}
+		delete(fields, f.Name)
+	})
+	for name := range fields {
+		t.Errorf("Config has a setting %q with no flag", name)
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	c, err := readConfig(writeConfig(t, `{"rows": 80, "rule": "B36/S23", "wrap": false, "threshold": 0.25}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Rows == nil || *c.Rows != 80 || c.Rule == nil || *c.Rule != "B36/S23" || c.Wrap == nil || *c.Wrap || c.Threshold == nil || *c.Threshold != 0.25 {
+		t.Errorf("readConfig = %+v", c)
+	}
+	if c.Columns != nil {
+		t.Errorf("readConfig set columns to %d, which the file left out", *c.Columns)
+	}
+}
+
+func TestReadConfigInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"rows": 80, "colums": 80}`,
+		`{"config": "other.json"}`,
+		`{"rows": "eighty"}`,
+		`{"wrap": 1}`,
+		`[]`,
+	} {
+		if _, err := readConfig(writeConfig(t, data)); err == nil {
+			t.Errorf("readConfig(%s) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := saveConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	c, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, currentConfig()) {
+		t.Errorf("saved config read back as %+v, want %+v", c, currentConfig())
+	}
+	if c.Seed != nil {
+		t.Error("saved config has a -seed that was never set")
+	}
+}
+
+// testFlags returns a flag set with a few of the settings' flags, so tests
+// can set them without touching the command line's.
+func testFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("rows", 40, "")
+	fs.Int("columns", 40, "")
+	fs.Int("fps", 10, "")
+	fs.Int("maxage", 50, "")
+	fs.Float64("threshold", 0.15, "")
+	return fs
+}
+
+// flagValue returns the value of the named flag in fs.
+func flagValue(fs *flag.FlagSet, name string) interface{} {
+	return fs.Lookup(name).Value.(flag.Getter).Get()
+}
+
+func TestConfigApply(t *testing.T) {
+	fs := testFlags()
+	maxAgeValue := 80
+	c := &Config{MaxAge: &maxAgeValue}
+	if err := c.apply(fs); err != nil {
+		t.Fatal(err)
+	}
+	if got := flagValue(fs, "maxage"); got != 80 {
+		t.Errorf("-maxage is %v after applying the config, want 80", got)
+	}
+}
+
+func TestConfigApplyKeepsSetFlags(t *testing.T) {
+	fs := testFlags()
+	if err := fs.Parse([]string{"-maxage", "60"}); err != nil {
+		t.Fatal(err)
+	}
+	maxAgeValue := 80
+	c := &Config{MaxAge: &maxAgeValue}
+	if err := c.apply(fs); err != nil {
+		t.Fatal(err)
+	}
+	if got := flagValue(fs, "maxage"); got != 60 {
+		t.Errorf("-maxage is %v after applying the config, want 60 from the command line", got)
+	}
+}
+
+func TestLoadSettingsPrecedence(t *testing.T) {
+	// -rows is given on the command line, and the environment and the
+	// config file both try to override it.
+	fs := testFlags()
+	if err := fs.Parse([]string{"-rows", "60"}); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"GOL_ROWS": "99", "GOL_FPS": "30"}
+	path := writeConfig(t, `{"rows": 70, "fps": 20, "columns": 70}`)
+	if err := loadSettings(fs, path, func(name string) string { return env[name] }); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := flagValue(fs, "rows"); got != 60 {
+		t.Errorf("-rows is %v, want 60 from the command line over the environment and config", got)
+	}
+	if got := flagValue(fs, "fps"); got != 30 {
+		t.Errorf("-fps is %v, want 30 from GOL_FPS over the config", got)
+	}
+	if got := flagValue(fs, "columns"); got != 70 {
+		t.Errorf("-columns is %v, want 70 from the config", got)
+	}
+	if got := flagValue(fs, "threshold"); got != 0.15 {
+		t.Errorf("-threshold is %v, want its default 0.15 with nothing setting it", got)
+	}
+}
+
+func TestLoadSettingsInvalidEnv(t *testing.T) {
+	env := map[string]string{"GOL_DENSITY": "lots"}
+	err := loadSettings(testFlags(), "", func(name string) string { return env[name] })
+	if err == nil || !strings.HasPrefix(err.Error(), "GOL_DENSITY: ") {
+		t.Errorf("loadSettings with GOL_DENSITY=lots returned %v, want an error naming it", err)
+	}
+}
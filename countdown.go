@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+	"time"
+)
+
+var countdown = flag.Int("countdown", 0, "show the starting board for this many seconds, counting down in the window title, before it starts stepping; any key starts it at once")
+
+// countdownLeft returns how many whole seconds, rounded up, are left at now of
+// a countdown ending at end, or 0 once it's over.
+func countdownLeft(end, now time.Time) int {
+	left := end.Sub(now)
+	if left <= 0 {
+		return 0
+	}
+	return int((left + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountdownLeft(t *testing.T) {
+	end := time.Date(2026, 1, 1, 0, 0, 3, 0, time.UTC)
+	tests := []struct {
+		before time.Duration
+		want   int
+	}{
+		{3 * time.Second, 3},
+		{2500 * time.Millisecond, 3},
+		{2 * time.Second, 2},
+		{time.Millisecond, 1},
+		{0, 0},
+		{-time.Second, 0},
+	}
+	for _, tt := range tests {
+		if got := countdownLeft(end, end.Add(-tt.before)); got != tt.want {
+			t.Errorf("countdownLeft %v before the end = %d, want %d", tt.before, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/aculler/conway-gol/life"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// cursor is a cell highlighted by the keyboard, so the board can be edited
+// without a mouse.
+type cursor struct {
+	drawable uint32
+	vbo      uint32
+
+	x int
+	y int
+
+	rows    int
+	columns int
+
+	// corners is the number of corners in the outline: four, or six for a
+	// hexagon.
+	corners int32
+}
+
+// newCursor returns a cursor over the bottom-left cell of a rows by columns
+// grid, with an outline ready to draw.
+func newCursor(rows, columns int) *cursor {
+	c := &cursor{rows: rows, columns: columns}
+
+	gl.GenBuffers(1, &c.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*6*3, nil, gl.DYNAMIC_DRAW)
+
+	gl.GenVertexArrays(1, &c.drawable)
+	gl.BindVertexArray(c.drawable)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil)
+
+	c.update()
+	return c
+}
+
+// delete frees the cursor's GL objects.
+func (c *cursor) delete() {
+	gl.DeleteVertexArrays(1, &c.drawable)
+	gl.DeleteBuffers(1, &c.vbo)
+}
+
+// move shifts the cursor by dx, dy, wrapping around the edges of the board.
+func (c *cursor) move(cells [][]*life.Cell, dx, dy int) {
+	c.x = (c.x + dx + len(cells)) % len(cells)
+	c.y = (c.y + dy + len(cells[c.x])) % len(cells[c.x])
+	c.update()
+}
+
+// resize fits the cursor to a rows by columns grid, for a board that has
+// changed size. It stays over the same cell when that's still on the board,
+// which it isn't if the board grew on the left or bottom.
+func (c *cursor) resize(rows, columns int) {
+	c.rows, c.columns = rows, columns
+	if c.x >= rows {
+		c.x = rows - 1
+	}
+	if c.y >= columns {
+		c.y = columns - 1
+	}
+	c.update()
+}
+
+// toggle flips the cell under the cursor between alive and dead.
+func (c *cursor) toggle(board *life.Board) {
+	toggle(board, c.x, c.y)
+}
+
+// update moves the outline to the cell under the cursor.
+func (c *cursor) update() {
+	corners := cellOutline(c.x, c.y, c.rows, c.columns)
+	c.corners = int32(len(corners) / 3)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(corners), gl.Ptr(corners))
+}
+
+func (c *cursor) draw(colorLocation int32) {
+	gl.Uniform4f(colorLocation, 1, 1, 1, 1)
+
+	gl.BindVertexArray(c.drawable)
+	gl.DrawArrays(gl.LINE_LOOP, 0, c.corners)
+}
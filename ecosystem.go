@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	energyRegen = flag.Float64("energy-regen", 0.01, "energy each position regains per generation in ecosystem mode")
+	energyCost  = flag.Float64("energy-cost", 0.6, "energy a birth uses up in ecosystem mode")
+)
+
+// energyColor tints an empty position by how much energy it has left.
+func energyColor(c *life.Cell) [4]float32 {
+	e := float32(c.Energy())
+	return [4]float32{0, 0.2 * e, 0.08 * e, 1}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/aculler/conway-gol/life"
+)
+
+// toggle flips the cell at x, y between alive and dead by hand.
+func toggle(board *life.Board, x, y int) {
+	if *mode == modeSmooth {
+		c := board.Cells[x][y]
+		if c.State < 0.5 {
+			c.State = 1
+		} else {
+			c.State = 0
+		}
+		return
+	}
+
+	board.Toggle(x, y)
+}
+
+// gridPosition converts a position in window coordinates, such as the mouse
+// cursor's, to the cell under it on a rows by columns grid filling a window
+// of the given size. Window coordinates start at the top left, while the
+// grid's y counts up from the bottom. ok is false outside the grid.
+func gridPosition(px, py float64, width, height, rows, columns int) (x, y int, ok bool) {
+	if px < 0 || py < 0 || px >= float64(width) || py >= float64(height) {
+		return 0, 0, false
+	}
+
+	x = int(px / float64(width) * float64(rows))
+	y = columns - 1 - int(py/float64(height)*float64(columns))
+	return x, y, true
+}
+
+// snapshot returns which cells are alive, indexed the same way as the grid.
+func snapshot(cells [][]*life.Cell) [][]bool {
+	board := make([][]bool, len(cells))
+	for x := range cells {
+		board[x] = make([]bool, len(cells[x]))
+		for y, c := range cells[x] {
+			board[x][y] = c.Alive()
+		}
+	}
+	return board
+}
+
+// restore sets the grid's cells from a board taken with snapshot.
+func restore(cells [][]*life.Cell, board [][]bool) {
+	for x := range cells {
+		for y, c := range cells[x] {
+			c.Set(board[x][y])
+		}
+	}
+}
+
+// boardsEqual reports whether the cells alive on the grid are exactly those
+// in prev, a board taken with snapshot.
+func boardsEqual(prev [][]bool, cells [][]*life.Cell) bool {
+	for x := range cells {
+		for y, c := range cells[x] {
+			if c.Alive() != prev[x][y] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// boundingBox returns the smallest rectangle holding every live cell on the
+// board. It never wraps around an edge. ok is false if nothing is alive.
+func boundingBox(board [][]bool) (minX, minY, maxX, maxY int, ok bool) {
+	for x := range board {
+		for y, alive := range board[x] {
+			if !alive {
+				continue
+			}
+
+			if !ok {
+				minX, minY, maxX, maxY = x, y, x, y
+				ok = true
+				continue
+			}
+
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+	return minX, minY, maxX, maxY, ok
+}
+
+// shift returns a copy of the board moved by dx, dy. Cells moved past an
+// edge are dropped rather than wrapped.
+func shift(board [][]bool, dx, dy int) [][]bool {
+	moved := make([][]bool, len(board))
+	for x := range board {
+		moved[x] = make([]bool, len(board[x]))
+	}
+
+	for x := range board {
+		for y, alive := range board[x] {
+			nx, ny := x+dx, y+dy
+			if !alive || nx < 0 || nx >= len(moved) || ny < 0 || ny >= len(moved[nx]) {
+				continue
+			}
+			moved[nx][ny] = true
+		}
+	}
+	return moved
+}
+
+// centerPattern returns a copy of the board with its live cells moved so their
+// bounding box sits in the middle. ok is false if nothing is alive.
+//
+// On a torus a pattern straddling an edge has no single sensible center. The
+// bounding box used here never wraps, so such a pattern is centered as if the
+// edge cut it in two, and a warning is logged.
+//
+// On a hex grid the pattern is only ever moved an even number of cells in y,
+// as an odd shift would give every cell the other set of neighbors, so it can
+// end up a cell below or above the middle.
+func centerPattern(board [][]bool, hex bool) ([][]bool, bool) {
+	minX, minY, maxX, maxY, ok := boundingBox(board)
+	if !ok {
+		return nil, false
+	}
+
+	rows, columns := len(board), len(board[0])
+	if (minX == 0 && maxX == rows-1) || (minY == 0 && maxY == columns-1) {
+		logWarnf("pattern touches opposite edges and may wrap; centering its unwrapped bounding box")
+	}
+
+	dx := (rows-(maxX-minX+1))/2 - minX
+	dy := (columns-(maxY-minY+1))/2 - minY
+	if hex {
+		dy -= dy % 2
+	}
+	return shift(board, dx, dy), true
+}
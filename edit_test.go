@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+func TestCenterPattern(t *testing.T) {
+	// A blinker at the left edge of a 7x8 board, one cell up.
+	board := make([][]bool, 7)
+	for x := range board {
+		board[x] = make([]bool, 8)
+	}
+	board[0][1], board[0][2], board[0][3] = true, true, true
+
+	tests := []struct {
+		name string
+		hex  bool
+		want [][2]int
+	}{
+		// It moves across three of the six spare rows, and up one so
+		// there are two spare columns below it and three above.
+		{"square", false, [][2]int{{3, 2}, {3, 3}, {3, 4}}},
+		// Moving up one would change its neighbors on a hex grid, so it
+		// stays where it is in y.
+		{"hex", true, [][2]int{{3, 1}, {3, 2}, {3, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			centered, ok := centerPattern(board, tt.hex)
+			if !ok {
+				t.Fatal("centerPattern found nothing alive")
+			}
+			checkPattern(t, centered, 7, 8, tt.want)
+		})
+	}
+}
+
+func TestCenterPatternHexEvenShift(t *testing.T) {
+	// A hex board with a glider-like shape one row up from the bottom
+	// needs an odd shift of three to be centered. It has to move two
+	// instead, so that stepping it still gives what the uncentered board
+	// would, moved along.
+	const rows, columns = 12, 12
+	r, err := life.ParseRule("B2/S34")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := make([][]bool, rows)
+	for x := range board {
+		board[x] = make([]bool, columns)
+	}
+	for _, p := range [][2]int{{2, 1}, {3, 1}, {2, 2}, {4, 3}} {
+		board[p[0]][p[1]] = true
+	}
+
+	centered, ok := centerPattern(board, true)
+	if !ok {
+		t.Fatal("centerPattern found nothing alive")
+	}
+	minX, minY, _, _, _ := boundingBox(centered)
+	dx, dy := minX-2, minY-1
+	if dy%2 != 0 {
+		t.Fatalf("pattern moved %d cells in y, want an even number", dy)
+	}
+
+	step := func(cells [][]bool) [][]bool {
+		b := life.NewBoard(rows, columns, r)
+		b.Neighborhood = life.Hexagonal
+		restore(b.Cells, cells)
+		b.Step()
+		return snapshot(b.Cells)
+	}
+	want := shift(step(board), dx, dy)
+	if got := step(centered); !equalPatterns(got, want) {
+		t.Error("centered pattern stepped differently from the original")
+	}
+}
+
+// equalPatterns reports whether a and b have the same cells alive.
+func equalPatterns(a, b [][]bool) bool {
+	for x := range a {
+		for y := range a[x] {
+			if a[x][y] != b[x][y] {
+				return false
+			}
+		}
+	}
+	return true
+}
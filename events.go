@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+)
+
+// The types of event in an -events timeline.
+const (
+	eventStart      = "start"
+	eventExtinct    = "extinct"
+	eventStable     = "stable"
+	eventOscillator = "oscillator"
+	eventSpaceship  = "spaceship"
+	eventPopulation = "population"
+	eventPattern    = "pattern"
+)
+
+var (
+	eventsFile = flag.String("events", "", "write a JSON timeline of the run, such as the board dying, settling, oscillating and passing population milestones, to this file when it ends")
+)
+
+// event is one entry in the timeline: what happened at which generation,
+// with any numbers that go with it, such as an oscillator's period.
+type event struct {
+	Generation int            `json:"generation"`
+	Type       string         `json:"type"`
+	Detail     map[string]int `json:"detail,omitempty"`
+}
+
+// eventLog collects the events of a run, which are written to a file as a
+// JSON array once it ends.
+type eventLog struct {
+	f      *os.File
+	events []event
+
+	// milestone is the next population reaching which is an event: each
+	// power of ten above the starting population.
+	milestone int
+}
+
+// newEventLog creates the file at path, so a path that can't be written
+// fails before the run rather than after it.
+func newEventLog(path string) (*eventLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLog{f: f, events: []event{}}, nil
+}
+
+// add records an event at a generation. detail may be nil.
+func (l *eventLog) add(generation int, typ string, detail map[string]int) {
+	l.events = append(l.events, event{Generation: generation, Type: typ, Detail: detail})
+}
+
+// start records a board starting, or starting over, with live cells, and
+// sets the first population milestone above it.
+func (l *eventLog) start(live int) {
+	l.add(0, eventStart, map[string]int{"population": live})
+	l.milestone = 10
+	for l.milestone <= live {
+		l.milestone *= 10
+	}
+}
+
+// population records each milestone the population has reached for the first
+// time by a generation.
+func (l *eventLog) population(generation, live int) {
+	for live >= l.milestone {
+		l.add(generation, eventPopulation, map[string]int{"milestone": l.milestone, "population": live})
+		l.milestone *= 10
+	}
+}
+
+// close writes the events to the file and closes it. A failure is logged,
+// since the run is over by then.
+func (l *eventLog) close() {
+	data, err := json.MarshalIndent(l.events, "", "\t")
+	if err == nil {
+		_, err = l.f.Write(append(data, '\n'))
+	}
+	if closeErr := l.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logErrorf("Failed to write events: %v", err)
+		return
+	}
+	logInfof("Saved events to %s", l.f.Name())
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEventLog(t *testing.T) {
+	defer func(p int) { *detectPeriod = p }(*detectPeriod)
+	*detectPeriod = 4
+
+	tests := []struct {
+		name  string
+		rle   string
+		steps int
+		want  []event
+	}{
+		{"blinker", "x = 3, y = 1\n3o!", 4, []event{
+			{0, eventStart, map[string]int{"population": 3}},
+			{2, eventOscillator, map[string]int{"period": 2}},
+		}},
+		{"pre-block", "x = 2, y = 2\no$2o!", 4, []event{
+			{0, eventStart, map[string]int{"population": 3}},
+			{2, eventStable, nil},
+		}},
+		{"domino", "x = 2, y = 1\n2o!", 3, []event{
+			{0, eventStart, map[string]int{"population": 2}},
+			{1, eventExtinct, nil},
+		}},
+
+		// The R-pentomino's population first reaches 10 at generation 6,
+		// with 12 cells, and a milestone is only reported once.
+		{"r-pentomino", "x = 3, y = 3\nb2o$2o$bo!", 12, []event{
+			{0, eventStart, map[string]int{"population": 5}},
+			{6, eventPopulation, map[string]int{"milestone": 10, "population": 12}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &eventLog{}
+			g := newGame(newTestBoard(40, 40, mustParseRLE(tt.rle), 18, 18), nil, nil, nil, events)
+			for i := 0; i < tt.steps; i++ {
+				g.Step()
+			}
+			if !reflect.DeepEqual(events.events, tt.want) {
+				t.Errorf("events = %v, want %v", events.events, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventLogMilestones(t *testing.T) {
+	l := &eventLog{}
+	l.start(42)
+	l.population(1, 99)
+	l.population(2, 2500)
+	l.population(3, 50)
+	l.population(4, 9000)
+
+	// 100 and 1000 are both passed at generation 2, and 10 was passed
+	// before the start.
+	want := []event{
+		{0, eventStart, map[string]int{"population": 42}},
+		{2, eventPopulation, map[string]int{"milestone": 100, "population": 2500}},
+		{2, eventPopulation, map[string]int{"milestone": 1000, "population": 2500}},
+	}
+	if !reflect.DeepEqual(l.events, want) {
+		t.Errorf("events = %v, want %v", l.events, want)
+	}
+}
+
+func TestEventLogClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	l, err := newEventLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.close()
+
+	// A run without events still writes an empty timeline.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[]\n" {
+		t.Errorf("empty timeline written as %q, want []", data)
+	}
+
+	l, err = newEventLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.start(3)
+	l.add(7, eventOscillator, map[string]int{"period": 2})
+	l.close()
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []event
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, l.events) {
+		t.Errorf("read back %v, want %v", got, l.events)
+	}
+}
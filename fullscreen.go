@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// windowPlacement is where a window sat on screen and how large it was.
+type windowPlacement struct {
+	x, y          int
+	width, height int
+}
+
+// toggleFullscreen switches the window between windowed and fullscreen on the
+// primary monitor. Going fullscreen saves the window's placement in windowed,
+// and coming back restores it. The viewport follows along through the
+// framebuffer size callback.
+func toggleFullscreen(window *glfw.Window, windowed *windowPlacement) {
+	if window.GetMonitor() != nil {
+		window.SetMonitor(nil, windowed.x, windowed.y, windowed.width, windowed.height, 0)
+		return
+	}
+
+	monitor := glfw.GetPrimaryMonitor()
+	if monitor == nil {
+		return
+	}
+	mode := monitor.GetVideoMode()
+
+	windowed.x, windowed.y = window.GetPos()
+	windowed.width, windowed.height = window.GetSize()
+	window.SetMonitor(monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"github.com/aculler/conway-gol/life"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Game is the simulation behind a run: the board, what's tracked about it from
+// one generation to the next, and how it's drawn. Step needs no window or GL
+// context, so the render loop and headless runs share it; Render draws it in
+// a window, leaving the loop to do the timing and input around the two.
+type Game struct {
+	board  *life.Board
+	kernel *smoothKernel
+	growth *grower
+
+	// stats, events, collector, periods and spaceships are nil unless
+	// -stats, -events, -http, -detect-period and -detect-spaceship are set.
+	stats      *statsWriter
+	events     *eventLog
+	collector  *statsCollector
+	periods    *periodDetector
+	spaceships *spaceshipDetector
+
+	generation int
+
+	// undo holds the board from before the last centering, if it can still
+	// be undone.
+	undo [][]bool
+
+	// extinct is set once the whole board has died, so it's only reported
+	// once. Cells toggled back to life clear it.
+	extinct bool
+
+	// stable holds the board once it has stopped changing, and stepping
+	// stops until it's edited. Smooth and ecosystem boards can go on
+	// changing underneath an unchanged set of live cells, so they never
+	// settle, and neither do Generations boards while their dead cells are
+	// still fading, or boards whose rule is morphing into another.
+	stable    [][]bool
+	canSettle bool
+
+	// watch is nil unless -watchfor is set. match is where it last found
+	// the pattern, until the board steps on, and spotted is set when it's
+	// found until the render loop has paused for it.
+	watch   *watcher
+	match   *patternMatch
+	spotted bool
+
+	// renderer and lines draw the board in a window, and are nil on a
+	// headless run. lines is also nil unless -gridlines is set, zones
+	// unless -zonerules is, and highlight until a match is drawn.
+	renderer  *cellRenderer
+	lines     *gridLines
+	zones     *zoneLines
+	highlight *matchHighlight
+}
+
+// newGame starts a game on board at generation 0. kernel is needed with -mode
+// smooth, and growth, stats and events may be nil.
+func newGame(board *life.Board, kernel *smoothKernel, growth *grower, stats *statsWriter, events *eventLog) *Game {
+	g := &Game{
+		board:  board,
+		kernel: kernel,
+		growth: growth,
+		stats:  stats,
+		events: events,
+
+		canSettle: (*mode == modeLife || *mode == modeSpacetime) && *states == 2 && *morphGens == 0,
+	}
+	if *detectPeriod > 0 && *mode != modeSmooth {
+		g.periods = newPeriodDetector(*detectPeriod)
+	}
+	if *detectSpaceship > 0 && *mode != modeSmooth {
+		g.spaceships = newSpaceshipDetector(*detectSpaceship)
+	}
+	g.reset(board)
+	return g
+}
+
+// reset starts the game over on a new board, at generation 0.
+func (g *Game) reset(board *life.Board) {
+	g.board = board
+	g.generation = 0
+	g.undo, g.stable, g.extinct = nil, nil, false
+	g.match, g.spotted = nil, false
+
+	if g.stats != nil {
+		g.stats.record(0, population(board), 0, 0)
+	}
+	if g.events != nil {
+		g.events.start(population(board))
+	}
+	if g.collector != nil {
+		g.collector.start(population(board))
+	}
+	if g.periods != nil {
+		g.periods.reset()
+		g.periods.observe(board.Cells)
+	}
+	if g.spaceships != nil {
+		g.spaceships.reset()
+		g.spaceships.observe(board)
+	}
+	if g.watch != nil {
+		g.watch.reset()
+		g.look()
+	}
+}
+
+// collect has c keep the game's statistics, from the current board on.
+func (g *Game) collect(c *statsCollector) {
+	g.collector = c
+	c.start(population(g.board))
+}
+
+// addEvent records an event at a generation in the -events timeline and
+// counts it for -http, whichever are set. detail may be nil.
+func (g *Game) addEvent(generation int, typ string, detail map[string]int) {
+	if g.events != nil {
+		g.events.add(generation, typ, detail)
+	}
+	if g.collector != nil {
+		g.collector.event(typ)
+	}
+}
+
+// watchFor starts looking for a pattern with w, from the current board on.
+func (g *Game) watchFor(w *watcher) {
+	g.watch = w
+	g.look()
+}
+
+// look checks the board for the watched pattern, reporting the first time
+// it's found.
+func (g *Game) look() {
+	m, ok := g.watch.find(snapshot(g.board.Cells), g.board.Wrap)
+	if !ok {
+		return
+	}
+
+	logResultf("Pattern found at %d,%d at generation %d", m.x, m.y, g.generation)
+	g.match, g.spotted = m, true
+	g.addEvent(g.generation, eventPattern, map[string]int{"x": m.x, "y": m.y})
+}
+
+// Step advances the board a generation, reporting whether it did. A stable
+// board isn't stepped again until it's edited. With -grow the board grows
+// first, so patterns never reach the edge, which also drops the undo since it
+// can't be restored onto a board of a different size.
+func (g *Game) Step() bool {
+	if g.stable != nil && !boardsEqual(g.stable, g.board.Cells) {
+		g.stable = nil
+	}
+	if g.stable != nil {
+		return false
+	}
+
+	if g.growth != nil && g.growth.grow(g.board) {
+		g.undo = nil
+
+		// Growing on the left or bottom moves every cell, which would
+		// look like a spaceship.
+		if g.spaceships != nil {
+			g.spaceships.reset()
+		}
+	}
+
+	g.match = nil
+
+	var prev [][]bool
+	if g.canSettle {
+		prev = snapshot(g.board.Cells)
+	}
+
+	stepBoard(g.board, g.kernel)
+	g.generation++
+
+	live := population(g.board)
+	births, deaths := g.board.Changes()
+	if g.stats != nil {
+		g.stats.record(g.generation, live, births, deaths)
+	}
+	if g.events != nil {
+		g.events.population(g.generation, live)
+	}
+	if g.collector != nil {
+		g.collector.record(g.generation, live, births, deaths)
+	}
+	logDebugf("Generation %d: population %d, %d births, %d deaths", g.generation, live, births, deaths)
+
+	if live == 0 && !g.extinct {
+		logResultf("Every cell has died")
+		g.addEvent(g.generation, eventExtinct, nil)
+	}
+	g.extinct = live == 0
+
+	if prev != nil && live > 0 && boardsEqual(prev, g.board.Cells) {
+		logResultf("Board is stable")
+		g.stable = prev
+		g.addEvent(g.generation, eventStable, nil)
+	}
+
+	// The detectors only log what they find the first time, and the same
+	// goes for the events.
+	if g.periods != nil {
+		reported := g.periods.reported
+		if period := g.periods.observe(g.board.Cells); period > 1 && period != reported {
+			g.addEvent(g.generation, eventOscillator, map[string]int{"period": period})
+		}
+	}
+	if g.spaceships != nil {
+		reported := g.spaceships.reported
+		if period, dx, dy := g.spaceships.observe(g.board); period > 0 && [3]int{period, dx, dy} != reported {
+			g.addEvent(g.generation, eventSpaceship, map[string]int{"period": period, "dx": dx, "dy": dy})
+		}
+	}
+	if g.watch != nil {
+		g.look()
+	}
+	return true
+}
+
+// Render draws the board into the current viewport, panned and zoomed by
+// viewMatrix, with its grid lines over it. The renderer follows the board as
+// it grows. Like all drawing it has to run on the GL thread.
+func (g *Game) Render(viewMatrix mat4, program uint32, colorLocation, viewLocation int32) {
+	rows, columns := g.board.Rows(), g.board.Columns()
+	if rows != g.renderer.rows || columns != g.renderer.columns {
+		g.renderer.resize(rows, columns)
+		if g.lines != nil {
+			g.lines.resize(rows, columns)
+		}
+	}
+
+	if *colorByNeighbors {
+		g.board.CountNeighbors()
+	}
+	g.renderer.draw(g.board, viewMatrix)
+
+	gl.UseProgram(program)
+	gl.UniformMatrix4fv(viewLocation, 1, false, &viewMatrix[0])
+	if g.lines != nil {
+		g.lines.draw(colorLocation)
+	}
+	if g.zones != nil {
+		g.zones.draw(colorLocation)
+	}
+
+	// The highlight follows the match, and goes once the board has
+	// stepped on from it.
+	if g.highlight != nil && g.highlight.match != g.match {
+		g.highlight.delete()
+		g.highlight = nil
+	}
+	if g.match != nil {
+		if g.highlight == nil {
+			g.highlight = newMatchHighlight(g.match, rows, columns)
+		}
+		g.highlight.draw(colorLocation)
+	}
+}
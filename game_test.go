@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGameStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		rle     string
+		steps   int
+		want    int
+		stepped int // Steps that advanced the board before it settled.
+	}{
+		{"glider", "x = 3, y = 3\nbo$2bo$3o!", 8, 5, 8},
+		{"blinker", "x = 3, y = 1\n3o!", 7, 3, 7},
+
+		// A still life settles on its first step, which repeats the
+		// board, and isn't stepped again.
+		{"block", "x = 2, y = 2\n2o$2o!", 5, 4, 1},
+
+		// Three cells in an L fill in to a block, then settle.
+		{"pre-block", "x = 2, y = 2\no$2o!", 5, 4, 2},
+
+		{"lone cell", "x = 1, y = 1\no!", 3, 0, 3},
+		{"r-pentomino", "x = 3, y = 3\nb2o$2o$bo!", 4, 8, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(newTestBoard(40, 40, mustParseRLE(tt.rle), 18, 18), nil, nil, nil, nil)
+
+			var stepped int
+			for i := 0; i < tt.steps; i++ {
+				if g.Step() {
+					stepped++
+				}
+			}
+			if pop := population(g.board); pop != tt.want {
+				t.Errorf("population after %d steps = %d, want %d", tt.steps, pop, tt.want)
+			}
+			if stepped != tt.stepped || g.generation != tt.stepped {
+				t.Errorf("stepped %d times to generation %d, want %d", stepped, g.generation, tt.stepped)
+			}
+		})
+	}
+}
+
+func TestGameExtinct(t *testing.T) {
+	g := newGame(newTestBoard(10, 10, mustParseRLE("x = 2, y = 1\n2o!"), 4, 4), nil, nil, nil, nil)
+	g.Step()
+	if !g.extinct {
+		t.Error("a domino has died out but the game isn't extinct")
+	}
+
+	g.board.Place(mustParseRLE("x = 2, y = 2\n2o$2o!"), 4, 4, true)
+	g.Step()
+	if g.extinct {
+		t.Error("the game is still extinct after cells came back to life")
+	}
+}
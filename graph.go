@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+const (
+	// graphSamples is how many generations the population graph spans.
+	graphSamples = 120
+
+	// The graph sits in the bottom-left corner, in normalized device
+	// coordinates.
+	graphLeft   = -0.95
+	graphRight  = -0.35
+	graphBottom = -0.95
+	graphTop    = -0.65
+)
+
+var (
+	showGraph = flag.Bool("graph", false, "show the population graph from the start (toggle it with G)")
+)
+
+// graph is a small overlay plotting the population over recent generations,
+// scaled to the range seen in that window.
+type graph struct {
+	// populations is a ring buffer of recent populations; next is where the
+	// next one goes and filled how many slots hold one.
+	populations []int
+	next        int
+	filled      int
+
+	visible bool
+
+	background    uint32
+	backgroundVbo uint32
+	axes          uint32
+	axesVbo       uint32
+	curve         uint32
+	curveVbo      uint32
+}
+
+func newGraph() *graph {
+	g := &graph{
+		populations: make([]int, graphSamples),
+		visible:     *showGraph,
+	}
+
+	g.background, g.backgroundVbo = makeVao([]float32{
+		graphLeft, graphTop, 0,
+		graphLeft, graphBottom, 0,
+		graphRight, graphBottom, 0,
+
+		graphLeft, graphTop, 0,
+		graphRight, graphTop, 0,
+		graphRight, graphBottom, 0,
+	})
+	g.axes, g.axesVbo = makeVao([]float32{
+		graphLeft, graphTop, 0,
+		graphLeft, graphBottom, 0,
+
+		graphLeft, graphBottom, 0,
+		graphRight, graphBottom, 0,
+	})
+
+	gl.GenBuffers(1, &g.curveVbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, g.curveVbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*graphSamples*3, nil, gl.DYNAMIC_DRAW)
+
+	gl.GenVertexArrays(1, &g.curve)
+	gl.BindVertexArray(g.curve)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil)
+
+	return g
+}
+
+// record adds a generation's population, dropping the oldest once the graph
+// is full.
+func (g *graph) record(population int) {
+	g.populations[g.next] = population
+	g.next = (g.next + 1) % len(g.populations)
+	if g.filled < len(g.populations) {
+		g.filled++
+	}
+}
+
+// delete frees the graph's GL objects.
+func (g *graph) delete() {
+	vaos := []uint32{g.background, g.axes, g.curve}
+	vbos := []uint32{g.backgroundVbo, g.axesVbo, g.curveVbo}
+	gl.DeleteVertexArrays(int32(len(vaos)), &vaos[0])
+	gl.DeleteBuffers(int32(len(vbos)), &vbos[0])
+}
+
+// reset clears the graph for a new board.
+func (g *graph) reset() {
+	g.next = 0
+	g.filled = 0
+}
+
+func (g *graph) draw(colorLocation int32) {
+	if !g.visible || g.filled == 0 {
+		return
+	}
+
+	// Oldest first, so the newest population is at the right-hand end.
+	samples := make([]int, g.filled)
+	for i := range samples {
+		samples[i] = g.populations[(g.next-g.filled+i+len(g.populations))%len(g.populations)]
+	}
+
+	min, max := samples[0], samples[0]
+	for _, p := range samples {
+		if p < min {
+			min = p
+		}
+		if p > max {
+			max = p
+		}
+	}
+
+	const padding = 0.02
+	points := make([]float32, 0, len(samples)*3)
+	for i, p := range samples {
+		x := graphLeft + (graphRight-graphLeft)*float32(i)/float32(graphSamples-1)
+		y := float32(graphBottom+graphTop) / 2
+		if max > min {
+			scale := float32(p-min) / float32(max-min)
+			y = graphBottom + padding + (graphTop-graphBottom-2*padding)*scale
+		}
+		points = append(points, x, y, 0)
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, g.curveVbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(points), gl.Ptr(points))
+
+	gl.Uniform4f(colorLocation, 0.1, 0.1, 0.1, 1)
+	gl.BindVertexArray(g.background)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+
+	gl.Uniform4f(colorLocation, 0.6, 0.6, 0.6, 1)
+	gl.BindVertexArray(g.axes)
+	gl.DrawArrays(gl.LINES, 0, 4)
+
+	gl.Uniform4f(colorLocation, 0.2, 1, 0.2, 1)
+	gl.BindVertexArray(g.curve)
+	gl.DrawArrays(gl.LINE_STRIP, 0, int32(len(samples)))
+}
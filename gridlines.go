@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var (
+	gridLinesEnabled = flag.Bool("gridlines", false, "draw thin lines between cells")
+)
+
+// gridLines are the lines drawn along every boundary between two rows or two
+// columns of cells.
+type gridLines struct {
+	drawable uint32
+	vbo      uint32
+
+	vertices int32
+}
+
+func newGridLines(rows, columns int) *gridLines {
+	// Each line runs across the whole window, in normalized device
+	// coordinates, at the same positions cellPoints puts the cell edges.
+	var points []float32
+	for x := 1; x < rows; x++ {
+		edge := float32(x)*2/float32(rows) - 1
+		points = append(points, edge, -1, 0, edge, 1, 0)
+	}
+	for y := 1; y < columns; y++ {
+		edge := float32(y)*2/float32(columns) - 1
+		points = append(points, -1, edge, 0, 1, edge, 0)
+	}
+
+	g := &gridLines{vertices: int32(len(points) / 3)}
+	if g.vertices > 0 {
+		g.drawable, g.vbo = makeVao(points)
+	}
+	return g
+}
+
+// delete frees the lines' GL objects.
+func (g *gridLines) delete() {
+	if g.vertices == 0 {
+		return
+	}
+	gl.DeleteVertexArrays(1, &g.drawable)
+	gl.DeleteBuffers(1, &g.vbo)
+}
+
+// resize rebuilds the lines for a rows by columns grid, for a board that has
+// changed size.
+func (g *gridLines) resize(rows, columns int) {
+	g.delete()
+	*g = *newGridLines(rows, columns)
+}
+
+func (g *gridLines) draw(colorLocation int32) {
+	if g.vertices == 0 {
+		return
+	}
+
+	gl.Uniform4f(colorLocation, gridLinesColor[0], gridLinesColor[1], gridLinesColor[2], gridLinesColor[3])
+	gl.BindVertexArray(g.drawable)
+	gl.DrawArrays(gl.LINES, 0, g.vertices)
+}
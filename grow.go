@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"math/rand"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+// growMargin is how close, in cells, a live cell can come to the edge of a
+// growing board before it grows. Nothing moves faster than a cell a
+// generation, so any margin keeps patterns clear of the edge.
+const growMargin = 4
+
+var (
+	grow    = flag.Bool("grow", false, "grow the board as live cells near its edges, standing in for an unbounded field (turns off -wrap)")
+	growMax = flag.Int("grow-max", 1000, "most rows and columns a board grows to with -grow")
+)
+
+// grower grows a board with -grow, coloring the cells it adds.
+type grower struct {
+	colors *rand.Rand
+
+	// capped is set once the board has hit -grow-max, so that's only
+	// reported once.
+	capped bool
+}
+
+func newGrower(seed int64) *grower {
+	return &grower{colors: rand.New(rand.NewSource(seed))}
+}
+
+// grow makes room on the board if its live cells are near an edge, reporting
+// whether it changed size.
+func (g *grower) grow(board *life.Board) bool {
+	grew, capped := board.Grow(growMargin, *growMax, *growMax)
+	if capped && !g.capped {
+		logWarnf("Board reached the -grow-max limit of %dx%d and can't grow any further", *growMax, *growMax)
+	}
+	g.capped = g.capped || capped
+
+	if grew {
+		for x := range board.Cells {
+			for _, c := range board.Cells[x] {
+				if c.Color[3] == 0 {
+					c.Color = cellPalette.sample(g.colors)
+				}
+			}
+		}
+	}
+	return grew
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+// runHeadless steps the game without a window, printing every generation to
+// out. It runs until -generations have been printed after the starting
+// board, the board dies with -exit-on-death or it settles into a still life;
+// otherwise it runs until ctx is done.
+func runHeadless(ctx context.Context, g *Game, out io.Writer) {
+	w := bufio.NewWriter(out)
+
+	var pace pacer
+	for {
+		if g.generation > 0 {
+			w.WriteByte('\n')
+		}
+		printBoard(w, g.board.Cells)
+		if err := w.Flush(); err != nil {
+			logErrorf("Failed to write board: %v", err)
+			return
+		}
+
+		if *generations > 0 && g.generation >= *generations {
+			return
+		}
+		if population(g.board) == 0 && *exitOnDeath {
+			// Stepping reports the board dying, but not a board that
+			// started out empty.
+			if g.generation == 0 {
+				logResultf("Every cell has died")
+				g.addEvent(0, eventExtinct, nil)
+			}
+			return
+		}
+
+		g.Step()
+		if g.stable != nil {
+			return
+		}
+
+		if ctx.Err() != nil {
+			logInfof("Interrupted")
+			return
+		}
+		if !*fast {
+			time.Sleep(pace.delay(time.Now(), frameInterval()))
+		}
+	}
+}
+
+// printBoard writes the board as text, top row first, with # for live cells
+// and . for dead ones.
+func printBoard(w *bufio.Writer, cells [][]*life.Cell) {
+	for y := len(cells[0]) - 1; y >= 0; y-- {
+		for x := range cells {
+			c := cells[x][y]
+			if c.Alive() || (*mode == modeSmooth && c.State >= 0.5) {
+				w.WriteByte('#')
+			} else {
+				w.WriteByte('.')
+			}
+		}
+		w.WriteByte('\n')
+	}
+}
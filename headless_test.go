@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestHeadlessGolden(t *testing.T) {
+	// Liveness has to come out the same on every platform for a given
+	// seed, so these runs are checked against output saved from one. Each
+	// covers a different way the board is seeded and stepped.
+	tests := []struct {
+		name      string
+		seedStyle string
+		mode      string
+		rule      string
+		wrap      bool
+	}{
+		{"uniform", seedStyleUniform, modeLife, "B3/S23", true},
+		{"cluster-bounded", seedStyleCluster, modeLife, "B36/S23", false},
+		{"cluster", seedStyleCluster, modeLife, "B3/S23", true},
+		{"radial", seedStyleRadial, modeLife, "B3/S23", true},
+		{"noise", seedStyleNoise, modeLife, "B3/S23", true},
+		{"ecosystem", seedStyleUniform, modeEcosystem, "B3/S23", true},
+	}
+
+	defer func(s, m string, w, f bool, g int) {
+		*seedStyle, *mode, *wrap, *fast, *generations = s, m, w, f, g
+	}(*seedStyle, *mode, *wrap, *fast, *generations)
+	*fast, *generations = true, 30
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*seedStyle, *mode, *wrap = tt.seedStyle, tt.mode, tt.wrap
+			r, err := life.ParseRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			g := newGame(makeBoard(24, 16, 0.3, 225, nil, r), nil, nil, nil, nil)
+			runHeadless(context.Background(), g, &out)
+
+			path := filepath.Join("testdata", "headless-"+tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Errorf("output differs from %s; rerun with -update if the change is intended", path)
+			}
+		})
+	}
+}
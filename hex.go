@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"math"
+)
+
+// hexRule is the rule used with -hex unless -rule is given. Hexagonal cells
+// have fewer neighbors than square ones, so Conway's rule just dies out.
+const hexRule = "B2/S34"
+
+var (
+	hexGrid = flag.Bool("hex", false, "use a grid of hexagons, each with six neighbors, under -rule "+hexRule+" by default")
+)
+
+// hexSize returns the width of each hexagon on a rows by columns hex grid
+// filling the window, in normalized device coordinates, and its height from
+// point to point. Cells with odd y are shifted half a hexagon along x and
+// tucked a quarter of one into the cells below, so the grid is half a
+// hexagon wider and three quarters as tall as it would be with squares.
+func hexSize(rows, columns int) (width, height float32) {
+	return 2 / (float32(rows) + 0.5), 2 / (0.75*float32(columns) + 0.25)
+}
+
+// hexCenter returns the center of the hexagon at x, y.
+func hexCenter(x, y, rows, columns int) (cx, cy float32) {
+	width, height := hexSize(rows, columns)
+
+	cx = -1 + width/2 + float32(x)*width
+	if y%2 == 1 {
+		cx += width / 2
+	}
+	cy = -1 + height/2 + float32(y)*height*0.75
+	return cx, cy
+}
+
+// hexCorners returns the corners of the hexagon at x, y, counterclockwise
+// from its top point.
+func hexCorners(x, y, rows, columns int) [6][2]float32 {
+	width, height := hexSize(rows, columns)
+	cx, cy := hexCenter(x, y, rows, columns)
+
+	return [6][2]float32{
+		{cx, cy + height/2},
+		{cx - width/2, cy + height/4},
+		{cx - width/2, cy - height/4},
+		{cx, cy - height/2},
+		{cx + width/2, cy - height/4},
+		{cx + width/2, cy + height/4},
+	}
+}
+
+// hexPoints returns the hexagon at x, y as six triangles fanned out from its
+// center, the way cellPoints returns a square.
+func hexPoints(x, y, rows, columns int) []float32 {
+	cx, cy := hexCenter(x, y, rows, columns)
+	corners := hexCorners(x, y, rows, columns)
+
+	points := make([]float32, 0, 6*3*3)
+	for i := range corners {
+		next := corners[(i+1)%len(corners)]
+		points = append(points,
+			cx, cy, 0,
+			corners[i][0], corners[i][1], 0,
+			next[0], next[1], 0,
+		)
+	}
+	return points
+}
+
+// hexGridPosition is gridPosition for a hex grid, returning the cell whose
+// center is nearest the position.
+func hexGridPosition(px, py float64, width, height, rows, columns int) (x, y int, ok bool) {
+	if px < 0 || py < 0 || px >= float64(width) || py >= float64(height) {
+		return 0, 0, false
+	}
+
+	// Convert to normalized device coordinates, where y counts up.
+	nx := float32(px/float64(width)*2 - 1)
+	ny := float32(1 - py/float64(height)*2)
+
+	// The nearest center is in the band of cells along x that the position
+	// falls in or one either side of it, and in the same place along that
+	// band or, where the band is shifted, one before.
+	hexWidth, hexHeight := hexSize(rows, columns)
+	band := int((ny + 1) / (hexHeight * 0.75))
+	column := int((nx + 1) / hexWidth)
+
+	best := float32(math.Inf(1))
+	for cy := band - 1; cy <= band+1; cy++ {
+		if cy < 0 || cy >= columns {
+			continue
+		}
+		for _, candidate := range []int{column - 1, column} {
+			if candidate < 0 || candidate >= rows {
+				continue
+			}
+			hx, hy := hexCenter(candidate, cy, rows, columns)
+
+			// Compare in window pixels, since the grid is stretched to
+			// fill the window.
+			dx := (hx - nx) * float32(width)
+			dy := (hy - ny) * float32(height)
+			if d := dx*dx + dy*dy; d < best {
+				best, x, y, ok = d, candidate, cy, true
+			}
+		}
+	}
+	return x, y, ok
+}
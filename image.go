@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"math"
+	"os"
+
+	// Register the formats image.Decode can read.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	imageFitLetterbox = "letterbox"
+	imageFitStretch   = "stretch"
+)
+
+// imageThresholdStep is how far the [ and ] keys move -image-threshold with
+// -imageseed-interactive.
+const imageThresholdStep = 0.02
+
+var (
+	imageFile      = flag.String("image", "", "start from a PNG, JPEG or GIF image, with dark pixels as live cells, instead of a random board")
+	imageThreshold = flag.Float64("image-threshold", 0.5, "brightness from 0 to 1 below which a pixel of -image is a live cell")
+	imageFit       = flag.String("image-fit", imageFitLetterbox, "how -image is fitted to a grid of a different shape: "+imageFitLetterbox+" keeps its aspect ratio and centers it with dead cells around it, "+imageFitStretch+" fills the grid")
+
+	imageSeedInteractive = flag.Bool("imageseed-interactive", false, "with -image, start paused and let [ and ] lower and raise -image-threshold, redrawing the board, until it first steps")
+)
+
+// readImage decodes an image file.
+func readImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if errors.Is(err, image.ErrFormat) {
+		return nil, fmt.Errorf("%s: unsupported image format, want PNG, JPEG or GIF", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if bounds := img.Bounds(); bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, fmt.Errorf("%s: image is empty", path)
+	}
+	return img, nil
+}
+
+// imageToBoard samples an image down (or up) to a pattern for a grid of rows
+// by columns cells. Each cell takes the pixel under its center and is alive
+// when that pixel's brightness is below threshold. Transparent pixels count
+// as white.
+//
+// With fit set to letterbox the pattern keeps the image's aspect ratio and is
+// as large as fits on the grid, so stampPattern centers it with dead cells
+// filling the rest. With stretch it's exactly the size of the grid.
+func imageToBoard(img image.Image, rows, columns int, threshold float64, fit string) [][]bool {
+	bounds := img.Bounds()
+	imgWidth, imgHeight := bounds.Dx(), bounds.Dy()
+
+	// x runs across the rows of the grid and y up its columns.
+	width, height := rows, columns
+	if fit == imageFitLetterbox {
+		scale := math.Min(float64(rows)/float64(imgWidth), float64(columns)/float64(imgHeight))
+		width = int(math.Max(1, math.Round(float64(imgWidth)*scale)))
+		height = int(math.Max(1, math.Round(float64(imgHeight)*scale)))
+	}
+
+	pattern := make([][]bool, width)
+	for x := range pattern {
+		pattern[x] = make([]bool, height)
+		px := bounds.Min.X + (2*x+1)*imgWidth/(2*width)
+
+		for y := range pattern[x] {
+			// Images count rows down from the top, the grid counts up from
+			// the bottom.
+			py := bounds.Min.Y + (2*(height-1-y)+1)*imgHeight/(2*height)
+			pattern[x][y] = pixelBrightness(img.At(px, py).RGBA()) < threshold
+		}
+	}
+	return pattern
+}
+
+// stepThreshold moves a threshold by steps of imageThresholdStep, keeping it
+// between 0 and 1 and on a whole step so repeated presses don't drift.
+func stepThreshold(threshold float64, steps int) float64 {
+	t := math.Round(threshold/imageThresholdStep+float64(steps)) * imageThresholdStep
+	return math.Max(0, math.Min(1, t))
+}
+
+// pixelBrightness returns the luma of a premultiplied color from 0 to 1, as if it
+// were drawn over white. Each product is converted so that none is fused into
+// a multiply-add, which would put pixels near the threshold on a different
+// side of it on arm64.
+func pixelBrightness(r, g, b, a uint32) float64 {
+	luma := float64(0.299*float64(r)) + float64(0.587*float64(g)) + float64(0.114*float64(b))
+	return (luma + float64(0xffff-a)) / 0xffff
+}
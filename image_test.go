@@ -0,0 +1,110 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gradient returns an image fading from black on the left to white on the
+// right, one shade per column.
+func gradient(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetGray(x, y, color.Gray{uint8(x * 255 / (width - 1))})
+		}
+	}
+	return img
+}
+
+func TestImageToBoardThreshold(t *testing.T) {
+	img := gradient(100, 50)
+
+	// The darker the threshold lets in, the further right live cells
+	// reach, and each column is all alive or all dead.
+	for _, threshold := range []float64{0, 0.25, 0.5, 0.75, 1.01} {
+		pattern := imageToBoard(img, 20, 10, threshold, imageFitStretch)
+		if len(pattern) != 20 || len(pattern[0]) != 10 {
+			t.Fatalf("pattern is %dx%d, want 20x10", len(pattern), len(pattern[0]))
+		}
+
+		live := 0
+		for x := range pattern {
+			for y := range pattern[x] {
+				if pattern[x][y] != pattern[x][0] {
+					t.Fatalf("threshold %g: column %d is mixed", threshold, x)
+				}
+			}
+			if pattern[x][0] {
+				live++
+			}
+		}
+		if want := int(math.Ceil(threshold * 20)); live < want-1 || live > want+1 || (threshold == 0 && live != 0) {
+			t.Errorf("threshold %g: %d columns alive, want about %d", threshold, live, want)
+		}
+	}
+}
+
+func TestReadImage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gradient.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, gradient(40, 20)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	img, err := readImage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Letterboxed onto a square grid, the image keeps its shape.
+	pattern := imageToBoard(img, 10, 10, 0.5, imageFitLetterbox)
+	if len(pattern) != 10 || len(pattern[0]) != 5 {
+		t.Errorf("letterboxed pattern is %dx%d, want 10x5", len(pattern), len(pattern[0]))
+	}
+
+	if _, err := readImage(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("reading a missing image succeeded")
+	}
+}
+
+func TestStepThreshold(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		steps     int
+		want      float64
+	}{
+		{0.5, 1, 0.52},
+		{0.5, -1, 0.48},
+		{0.01, -1, 0},
+		{0.99, 1, 1},
+		{1, 1, 1},
+		{0, -3, 0},
+
+		// A threshold set off a step lands back on one.
+		{0.333, 1, 0.36},
+	}
+	for _, tt := range tests {
+		if got := stepThreshold(tt.threshold, tt.steps); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("stepThreshold(%g, %d) = %g, want %g", tt.threshold, tt.steps, got, tt.want)
+		}
+	}
+
+	// Fifty steps up from zero come to exactly one, not a hair off it.
+	threshold := 0.0
+	for i := 0; i < 50; i++ {
+		threshold = stepThreshold(threshold, 1)
+	}
+	if threshold != 1 {
+		t.Errorf("fifty steps up from 0 came to %v, want 1", threshold)
+	}
+}
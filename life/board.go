@@ -0,0 +1,486 @@
+// Package life runs life-like cellular automata on a finite grid, with no
+// knowledge of how the grid is drawn.
+package life
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Board is a grid of cells and the rules they evolve by.
+type Board struct {
+	// births and deaths count the cells that came alive and died in the
+	// last step. They're added to atomically, so they come first, where
+	// they're 64-bit aligned even on 32-bit platforms.
+	births int64
+	deaths int64
+
+	// Cells is indexed [x][y]. x runs over the rows of the board and y over
+	// its columns, counting up from the bottom.
+	Cells [][]*Cell
+
+	Rule Rule
+
+	// Zones are rectangles of the board that evolve by rules of their own
+	// in place of Rule. A cell in more than one goes by the first, and a
+	// cell in none by Rule. A cell goes by its own zone's rule whatever
+	// zones its neighbors are in. Zones stay where they are when the board
+	// grows.
+	Zones []Zone
+
+	// MorphRule is a rule the board moves over to from Rule as it steps,
+	// when MorphGenerations is above zero. At each step a cell goes by
+	// MorphRule in place of Rule with a chance of MorphBlend, which rises
+	// evenly from 0 to 1 over the first MorphGenerations steps. Whether it
+	// does is decided by hashing its position and the step with MorphSeed,
+	// so the same seed always morphs the same way. Cells in a zone go by
+	// the zone's rule.
+	MorphRule        Rule
+	MorphGenerations int
+	MorphSeed        int64
+
+	// steps counts the generations Step has moved the board on.
+	steps int
+
+	// OverrideRule is the rule cells go by while one of their neighbors is
+	// a live wild card, a cell marked with SetRuleOverride. It takes the
+	// place of Rule, a zone's rule, or a morph. overrides counts the wild
+	// cards, so boards without any don't look for them.
+	OverrideRule Rule
+	overrides    int
+
+	// Wrap joins opposite edges of the board into a torus. Without it, cells
+	// beyond the edges are dead.
+	Wrap bool
+
+	// CornerWrap lets the diagonal neighbors of corner cells wrap to the
+	// opposite corner.
+	CornerWrap bool
+
+	// Neighborhood is which surrounding cells count as neighbors.
+	Neighborhood Neighborhood
+
+	// Twist is the number of rows to shift by when wrapping across the left
+	// or right edge, making a twisted torus.
+	Twist int
+
+	// States is the number of states a cell has, as in the Generations
+	// family of rules. Two is ordinary life. With more, a cell that dies
+	// spends States-2 generations decaying before it's fully dead, as with
+	// Brian's Brain (B2/S with 3 states).
+	States int
+
+	// Immigration splits the live cells into two teams, with each newborn
+	// joining the team most of its parents are on.
+	Immigration bool
+
+	// Ecosystem makes every birth use up EnergyCost of its position's
+	// energy, which recovers by EnergyRegen each generation.
+	Ecosystem   bool
+	EnergyRegen float64
+	EnergyCost  float64
+
+	// flat is where Step counts neighbors from.
+	flat flatGrid
+}
+
+// Zone is a rectangle of a board with a rule of its own.
+type Zone struct {
+	// X0, Y0 is the zone's bottom-left cell, and X1, Y1 the corner just
+	// past its top-right one, so the zone is X1-X0 cells across.
+	X0, Y0, X1, Y1 int
+
+	Rule Rule
+}
+
+// Contains reports whether the cell at x, y is in the zone.
+func (z Zone) Contains(x, y int) bool {
+	return x >= z.X0 && x < z.X1 && y >= z.Y0 && y < z.Y1
+}
+
+// Neighborhood is a set of positions around a cell that count as its
+// neighbors.
+type Neighborhood int
+
+const (
+	// Moore is the eight cells surrounding a cell, diagonals included.
+	Moore Neighborhood = iota
+
+	// VonNeumann is the four cells orthogonally next to a cell, so a cell
+	// has at most four live neighbors.
+	VonNeumann
+
+	// Hexagonal is the six cells around a cell on a grid of hexagons. The
+	// cells with odd y are shifted half a cell further along x than those
+	// with even y, so each cell touches two cells above and two below.
+	Hexagonal
+)
+
+// neighborOffsets are the positions of each neighborhood relative to a cell.
+var neighborOffsets = map[Neighborhood][][2]int{
+	Moore: {
+		{-1, 0},  // To the left
+		{1, 0},   // To the right
+		{0, 1},   // Up
+		{0, -1},  // Down
+		{-1, 1},  // Top-left
+		{1, 1},   // Top-right
+		{-1, -1}, // Bottom-left
+		{1, -1},  // Bottom-right
+	},
+	VonNeumann: {
+		{-1, 0}, // To the left
+		{1, 0},  // To the right
+		{0, 1},  // Up
+		{0, -1}, // Down
+	},
+}
+
+// hexOffsets are the positions of the Hexagonal neighborhood relative to a
+// cell, for cells with even and odd y.
+var hexOffsets = [2][][2]int{
+	{
+		{-1, 0},  // To the left
+		{1, 0},   // To the right
+		{-1, 1},  // Up and left
+		{0, 1},   // Up and right
+		{-1, -1}, // Down and left
+		{0, -1},  // Down and right
+	},
+	{
+		{-1, 0}, // To the left
+		{1, 0},  // To the right
+		{0, 1},  // Up and left
+		{1, 1},  // Up and right
+		{0, -1}, // Down and left
+		{1, -1}, // Down and right
+	},
+}
+
+// offsets returns the positions of the neighborhood relative to a cell, which
+// on a hex grid depend on whether the cell's y is odd.
+func (n Neighborhood) offsets(y int) [][2]int {
+	if n == Hexagonal {
+		return hexOffsets[y&1]
+	}
+	return neighborOffsets[n]
+}
+
+// NewBoard returns an empty rows by columns board on a torus, evolving under
+// rule r.
+func NewBoard(rows, columns int, r Rule) *Board {
+	b := &Board{
+		Cells: make([][]*Cell, rows),
+		Rule:  r,
+
+		Wrap:       true,
+		CornerWrap: true,
+		States:     2,
+	}
+
+	for x := range b.Cells {
+		b.Cells[x] = make([]*Cell, columns)
+		for y := range b.Cells[x] {
+			b.Cells[x][y] = newCell(b, x, y)
+		}
+	}
+
+	return b
+}
+
+// ruleAt returns the rule the cell at x, y evolves by: that of the first of
+// the board's zones it's in, or else the board's, or the one it's morphing to
+// if the cell's draw for this step says so.
+func (b *Board) ruleAt(x, y int) *Rule {
+	for i := range b.Zones {
+		if b.Zones[i].Contains(x, y) {
+			return &b.Zones[i].Rule
+		}
+	}
+	if b.MorphGenerations > 0 && cellChance(b.MorphSeed, x, y, b.steps) < b.MorphBlend() {
+		return &b.MorphRule
+	}
+	return &b.Rule
+}
+
+// MorphBlend returns the chance that a cell goes by MorphRule in the next
+// step: 0 at first, rising evenly to 1 once MorphGenerations steps have
+// passed. It's 0 when the board isn't morphing.
+func (b *Board) MorphBlend() float64 {
+	if b.MorphGenerations <= 0 {
+		return 0
+	}
+	if b.steps >= b.MorphGenerations {
+		return 1
+	}
+	return float64(b.steps) / float64(b.MorphGenerations)
+}
+
+// cellChance returns a number from 0 up to 1 made by hashing seed with the
+// position x, y and step n. It's the same for the same arguments on every
+// platform and unrelated from one cell or step to the next, so cells can be
+// given random draws that don't depend on the order they're visited in.
+func cellChance(seed int64, x, y, n int) float64 {
+	h := uint64(seed)
+	for _, v := range [...]int{x, y, n} {
+		h = mix64(h ^ uint64(v))
+	}
+	return float64(h>>11) / (1 << 53)
+}
+
+// mix64 scrambles the bits of h, as the finalizer of SplitMix64 does.
+func mix64(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// Rows returns the number of rows on the board.
+func (b *Board) Rows() int {
+	return len(b.Cells)
+}
+
+// Columns returns the number of columns on the board.
+func (b *Board) Columns() int {
+	return len(b.Cells[0])
+}
+
+// Alive reports whether the cell at x, y is alive.
+func (b *Board) Alive(x, y int) bool {
+	return b.Cells[x][y].alive
+}
+
+// Step advances the board by one generation. Every cell's next state is
+// worked out from the current generation before any of them is committed, so
+// no cell sees a neighbor that has already moved on.
+//
+// That also means the cells can be worked through in any order, so both
+// passes are spread across every CPU. Neighbors are counted from a flat copy
+// of the board, which the commit pass keeps up to date for the next step.
+func (b *Board) Step() {
+	b.sync()
+	b.inBands(func(rows [][]*Cell) {
+		for _, row := range rows {
+			for _, c := range row {
+				c.checkState(b)
+			}
+		}
+	})
+
+	b.births, b.deaths = 0, 0
+	b.inBands(func(rows [][]*Cell) {
+		var births, deaths int64
+		for _, row := range rows {
+			for _, c := range row {
+				switch {
+				case !c.alive && c.aliveNext:
+					births++
+				case c.alive && !c.aliveNext:
+					deaths++
+				}
+				c.commit()
+				b.flat.set(b.flat.index(c.x, c.y), c)
+			}
+		}
+		atomic.AddInt64(&b.births, births)
+		atomic.AddInt64(&b.deaths, deaths)
+	})
+	b.steps++
+}
+
+// CountNeighbors records how many live neighbors every cell has in the
+// current generation, for Cell.Neighbors. Step counts them too, but only for
+// the generation before, which decided the current one; under most rules that
+// never shows a live cell as crowded.
+func (b *Board) CountNeighbors() {
+	b.sync()
+	b.inBands(func(rows [][]*Cell) {
+		for _, row := range rows {
+			for _, c := range row {
+				c.neighbors, _ = c.liveNeighbors(b)
+			}
+		}
+	})
+}
+
+// EachLive calls f for every live cell, in the same order as walking Cells.
+// It finds them in the board's flat copy, so dead cells are passed over
+// without visiting them.
+func (b *Board) EachLive(f func(x, y int, c *Cell)) {
+	b.sync()
+	g := &b.flat
+	for x := range b.Cells {
+		row := g.alive[g.index(x, 0) : g.index(x, 0)+len(b.Cells[x])]
+		for y, alive := range row {
+			if alive != 0 {
+				f(x, y, b.Cells[x][y])
+			}
+		}
+	}
+}
+
+// Changes returns how many cells came alive and how many died in the last
+// step.
+func (b *Board) Changes() (births, deaths int) {
+	return int(b.births), int(b.deaths)
+}
+
+// inBands splits the board's rows into one contiguous band per CPU Go may use,
+// as set by GOMAXPROCS, and calls f on every band concurrently. f must only
+// change cells within its band.
+func (b *Board) inBands(f func(rows [][]*Cell)) {
+	bands := runtime.GOMAXPROCS(0)
+	if bands > len(b.Cells) {
+		bands = len(b.Cells)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < bands; i++ {
+		wg.Add(1)
+		go func(rows [][]*Cell) {
+			defer wg.Done()
+			f(rows)
+		}(b.Cells[i*len(b.Cells)/bands : (i+1)*len(b.Cells)/bands])
+	}
+	wg.Wait()
+}
+
+// Population returns the number of live cells on the board.
+func (b *Board) Population() int {
+	var count int
+	for x := range b.Cells {
+		for _, c := range b.Cells[x] {
+			if c.alive {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Place brings to life every cell the pattern marks alive, with the pattern's
+// bottom-left corner at originX, originY. The pattern is indexed [x][y] like
+// the board, and the cells around its live ones are left as they were. Cells
+// past an edge wrap around when wrap is set, and are dropped otherwise; the
+// number dropped is returned.
+func (b *Board) Place(pattern [][]bool, originX, originY int, wrap bool) (clipped int) {
+	rows, columns := b.Rows(), b.Columns()
+
+	for px := range pattern {
+		for py, alive := range pattern[px] {
+			if !alive {
+				continue
+			}
+
+			x, y := originX+px, originY+py
+			if wrap {
+				x = (x%rows + rows) % rows
+				y = (y%columns + columns) % columns
+			} else if x < 0 || x >= rows || y < 0 || y >= columns {
+				clipped++
+				continue
+			}
+
+			if c := b.Cells[x][y]; !c.alive {
+				c.Set(true)
+			}
+		}
+	}
+	return clipped
+}
+
+// Toggle flips the cell at x, y between alive and dead.
+func (b *Board) Toggle(x, y int) {
+	c := b.Cells[x][y]
+	c.Set(!c.alive)
+}
+
+// Grow makes room around the live cells when any of them come within margin
+// cells of an edge, so patterns on a board without Wrap never feel the edge.
+// Each crowded side gets half the board's size again, but never more than
+// takes it past maxRows by maxColumns. The existing cells keep their state and
+// move to their new positions, and the new cells start dead.
+//
+// grew reports whether the board changed size, and capped whether a crowded
+// side couldn't be given any room because of the limit.
+func (b *Board) Grow(margin, maxRows, maxColumns int) (grew, capped bool) {
+	rows, columns := b.Rows(), b.Columns()
+
+	minX, minY, maxX, maxY := rows, columns, -1, -1
+	for x := range b.Cells {
+		for y, c := range b.Cells[x] {
+			if !c.alive {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+	if maxX < 0 {
+		return false, false
+	}
+
+	// room returns how much to add to one side of an axis size long when
+	// it's crowded, leaving space for the other side's share too.
+	room := func(crowded bool, size, limit, shared int) int {
+		if !crowded {
+			return 0
+		}
+		add := size / 2
+		if add < margin {
+			add = margin
+		}
+		if size+shared+add > limit {
+			add = limit - size - shared
+		}
+		if add <= 0 {
+			capped = true
+			return 0
+		}
+		return add
+	}
+
+	left := room(minX < margin, rows, maxRows, 0)
+	right := room(maxX >= rows-margin, rows, maxRows, left)
+	down := room(minY < margin, columns, maxColumns, 0)
+	if b.Neighborhood == Hexagonal && down%2 == 1 {
+		// Shifting a hex grid by an odd number of cells would move every
+		// cell into the other set of neighbors.
+		down--
+	}
+	up := room(maxY >= columns-margin, columns, maxColumns, down)
+	if left+right+down+up == 0 {
+		return false, capped
+	}
+
+	cells := make([][]*Cell, rows+left+right)
+	for x := range cells {
+		cells[x] = make([]*Cell, columns+down+up)
+		for y := range cells[x] {
+			ox, oy := x-left, y-down
+			if ox >= 0 && ox < rows && oy >= 0 && oy < columns {
+				c := b.Cells[ox][oy]
+				c.x, c.y = x, y
+				cells[x][y] = c
+			} else {
+				cells[x][y] = newCell(b, x, y)
+			}
+		}
+	}
+	b.Cells = cells
+	b.flat.stale = true
+
+	return true, capped
+}
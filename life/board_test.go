@@ -0,0 +1,677 @@
+package life
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+var conway, _ = ParseRule("B3/S23")
+
+// points are cell positions as x, y pairs, the way the board indexes them.
+type points [][2]int
+
+var (
+	blinker = points{{4, 3}, {4, 4}, {4, 5}}
+	block   = points{{2, 2}, {2, 3}, {3, 2}, {3, 3}}
+
+	// glider heads up and to the right, towards larger x and y.
+	glider = points{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+)
+
+// newTestBoard returns a rows by columns Conway board with the cells at p,
+// moved by dx, dy and wrapped around the edges, brought to life.
+func newTestBoard(rows, columns int, p points, dx, dy int) *Board {
+	b := NewBoard(rows, columns, conway)
+	setAlive(b, p, dx, dy)
+	return b
+}
+
+// setAlive brings to life the cells at p, moved by dx, dy and wrapped around
+// the edges of b.
+func setAlive(b *Board, p points, dx, dy int) {
+	for _, pt := range p {
+		x := ((pt[0]+dx)%b.Rows() + b.Rows()) % b.Rows()
+		y := ((pt[1]+dy)%b.Columns() + b.Columns()) % b.Columns()
+		b.Cells[x][y].Set(true)
+	}
+}
+
+// checkAlive fails t unless exactly the cells of want are alive on b.
+func checkAlive(t *testing.T, b *Board, want *Board) {
+	t.Helper()
+	for x := range b.Cells {
+		for y, c := range b.Cells[x] {
+			if c.Alive() != want.Alive(x, y) {
+				t.Errorf("cell %d,%d alive = %v, want %v", x, y, c.Alive(), want.Alive(x, y))
+			}
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name          string
+		rows, columns int
+		start         points
+		at            [2]int // Where the pattern starts.
+		steps         int
+		moved         [2]int // How far it should have moved.
+	}{
+		{"blinker", 10, 10, blinker, [2]int{0, 0}, 2, [2]int{0, 0}},
+		{"block", 6, 6, block, [2]int{0, 0}, 1, [2]int{0, 0}},
+		{"glider", 8, 8, glider, [2]int{0, 0}, 4, [2]int{1, 1}},
+
+		// Starting across the corner, the glider has to wrap both ways
+		// to keep its shape.
+		{"glider across the corner", 6, 6, glider, [2]int{4, 4}, 4, [2]int{1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(tt.rows, tt.columns, tt.start, tt.at[0], tt.at[1])
+			for i := 0; i < tt.steps; i++ {
+				b.Step()
+			}
+			want := newTestBoard(tt.rows, tt.columns, tt.start, tt.at[0]+tt.moved[0], tt.at[1]+tt.moved[1])
+			checkAlive(t, b, want)
+		})
+	}
+}
+
+func TestStepBlinkerPhase(t *testing.T) {
+	b := newTestBoard(10, 10, blinker, 0, 0)
+	b.Step()
+	checkAlive(t, b, newTestBoard(10, 10, points{{3, 4}, {4, 4}, {5, 4}}, 0, 0))
+}
+
+func TestZones(t *testing.T) {
+	noBirths, _ := ParseRule("B/S23")
+	noSurvival, _ := ParseRule("B3/S")
+
+	// The vertical blinker at x=4 turns horizontal, with the cells on
+	// either side of it born at x=3 and x=5 and its middle surviving.
+	tests := []struct {
+		name  string
+		zones []Zone
+		want  points
+	}{
+		{"none", nil, points{{3, 4}, {4, 4}, {5, 4}}},
+
+		// The birth at x=5 goes by the zone to the right, whatever the
+		// rule its parents are in.
+		{"no births on the right", []Zone{{5, 0, 10, 10, noBirths}}, points{{3, 4}, {4, 4}}},
+
+		// The blinker's middle is on the zone's edge, and goes by it.
+		{"no survival from the middle", []Zone{{4, 0, 10, 10, noSurvival}}, points{{3, 4}, {5, 4}}},
+
+		// Where zones overlap the first wins, and outside them Rule
+		// does.
+		{"first zone wins", []Zone{{5, 0, 10, 10, conway}, {0, 0, 10, 10, noBirths}}, points{{4, 4}, {5, 4}}},
+		{"outside every zone", []Zone{{0, 6, 10, 10, noSurvival}}, points{{3, 4}, {4, 4}, {5, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(10, 10, blinker, 0, 0)
+			b.Zones = tt.zones
+			b.Step()
+			checkAlive(t, b, newTestBoard(10, 10, tt.want, 0, 0))
+		})
+	}
+}
+
+func TestZonesCrossing(t *testing.T) {
+	// A glider crosses into a zone with the board's own rule as if the
+	// zone weren't there, so neighbors are counted across the boundary.
+	b := newTestBoard(12, 12, glider, 0, 0)
+	b.Zones = []Zone{{4, 4, 12, 12, conway}}
+	for i := 0; i < 16; i++ {
+		b.Step()
+	}
+	checkAlive(t, b, newTestBoard(12, 12, glider, 4, 4))
+
+	// Into a zone where nothing survives, it breaks up and whatever is
+	// left never settles inside it.
+	dead, _ := ParseRule("B3/S")
+	b = newTestBoard(12, 12, glider, 0, 0)
+	b.Zones = []Zone{{4, 0, 12, 12, dead}}
+	for i := 0; i < 40; i++ {
+		b.Step()
+		for x := 4; x < 12; x++ {
+			for y := range b.Cells[x] {
+				if b.Alive(x, y) && b.Cells[x][y].Age() > 0 {
+					t.Fatalf("generation %d: cell %d,%d survived inside the zone", i+1, x, y)
+				}
+			}
+		}
+	}
+	if b.Population() >= len(glider) {
+		t.Errorf("population %d after the glider hit the zone, want it broken up", b.Population())
+	}
+}
+
+func TestMorph(t *testing.T) {
+	// A full board where everything survives under the first rule and
+	// nothing under the second. Each step kills the cells whose draw
+	// picks the second rule, so the board thins out as the blend rises.
+	everything, _ := ParseRule("B/S012345678")
+	nothing, _ := ParseRule("B/S")
+	const morphGenerations = 4
+
+	b := NewBoard(64, 64, everything)
+	b.MorphRule, b.MorphGenerations, b.MorphSeed = nothing, morphGenerations, 208
+	for x := range b.Cells {
+		for _, c := range b.Cells[x] {
+			c.Set(true)
+		}
+	}
+
+	// The chance of surviving each step, and so roughly what's left, is
+	// what's left of the blend.
+	want := 64.0 * 64
+	for step := 0; step <= morphGenerations; step++ {
+		blend := float64(step) / morphGenerations
+		if got := b.MorphBlend(); got != blend {
+			t.Fatalf("step %d: blend %v, want %v", step, got, blend)
+		}
+
+		before := b.Population()
+		b.Step()
+		want *= 1 - blend
+		got := b.Population()
+		switch {
+		case step == 0 && got != before:
+			t.Errorf("step 0: population fell from %d to %d, want every cell under the first rule", before, got)
+		case step == morphGenerations && got != 0:
+			t.Errorf("step %d: population %d, want every cell under the second rule", step, got)
+		case math.Abs(float64(got)-want) > 0.1*want+10:
+			t.Errorf("step %d: population %d, want about %.0f with the blend at %v", step, got, want, blend)
+		}
+	}
+	if got := b.MorphBlend(); got != 1 {
+		t.Errorf("blend %v once morphed, want it to stay at 1", got)
+	}
+}
+
+func TestMorphReproducible(t *testing.T) {
+	// The draws depend on the seed, and only on the seed.
+	highLife, _ := ParseRule("B36/S23")
+	run := func(seed int64) *Board {
+		b := randomBoard(40, 40)
+		b.MorphRule, b.MorphGenerations, b.MorphSeed = highLife, 10, seed
+		for i := 0; i < 20; i++ {
+			b.Step()
+		}
+		return b
+	}
+
+	if a, b := run(1), run(1); !sameCells(a, b) {
+		t.Error("two morphs with the same seed came out differently")
+	}
+	if a, b := run(1), run(2); sameCells(a, b) {
+		t.Error("morphs with different seeds came out the same")
+	}
+}
+
+func TestRuleOverride(t *testing.T) {
+	// The cell at 5,5 has six live neighbors, which only brings it to life
+	// under HighLife, the override rule. The cell at 4,5 is the wild card.
+	highLife, _ := ParseRule("B36/S23")
+	sides := points{{4, 4}, {4, 5}, {4, 6}, {6, 4}, {6, 5}, {6, 6}}
+
+	tests := []struct {
+		name      string
+		wild      bool
+		wildAlive bool
+		born      bool
+	}{
+		{"no wild card", false, true, false},
+		{"live wild card", true, true, true},
+		// A dead wild card leaves its neighbors to the board's rule,
+		// and the five cells left are too few to be born from anyway.
+		{"dead wild card", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(10, 10, sides, 0, 0)
+			b.OverrideRule = highLife
+			b.Cells[4][5].SetRuleOverride(tt.wild)
+			b.Cells[4][5].Set(tt.wildAlive)
+			b.Step()
+
+			if b.Alive(5, 5) != tt.born {
+				t.Errorf("cell 5,5 alive = %v, want %v", b.Alive(5, 5), tt.born)
+			}
+			// The wild card goes by the board's rule itself: with two
+			// live neighbors it survives under Conway.
+			if tt.wildAlive && !b.Alive(4, 5) {
+				t.Error("the wild card died")
+			}
+		})
+	}
+}
+
+func TestRuleOverrideOwnRule(t *testing.T) {
+	// Only the corner of the block touching the wild card goes by the
+	// override rule, under which nothing survives. The rest of the block
+	// goes by Conway's and lives on, as does a second block far away.
+	nothing, _ := ParseRule("B/S")
+	b := newTestBoard(12, 12, block, -2, -2)
+	setAlive(b, block, 6, 6)
+	b.Wrap = false
+	b.OverrideRule = nothing
+	b.Cells[2][2].Set(true)
+	b.Cells[2][2].SetRuleOverride(true)
+	b.Step()
+
+	checkAlive(t, b, newTestBoard(12, 12, points{{0, 0}, {0, 1}, {1, 0}, {8, 8}, {8, 9}, {9, 8}, {9, 9}}, 0, 0))
+}
+
+// sameCells reports whether a and b have the same cells alive.
+func sameCells(a, b *Board) bool {
+	for x := range a.Cells {
+		for y := range a.Cells[x] {
+			if a.Alive(x, y) != b.Alive(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// randomBoard returns a rows by columns Conway board with about a third of
+// its cells alive, the same ones every time.
+func randomBoard(rows, columns int) *Board {
+	b := NewBoard(rows, columns, conway)
+	r := rand.New(rand.NewSource(1))
+	for x := range b.Cells {
+		for _, c := range b.Cells[x] {
+			c.Set(r.Float64() < 0.3)
+		}
+	}
+	return b
+}
+
+func BenchmarkStep(b *testing.B) {
+	for _, size := range []int{50, 200, 1000} {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			board := randomBoard(size, size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				board.Step()
+			}
+		})
+	}
+}
+
+// neighbors returns how many live neighbors the cell at x, y has on b.
+func neighbors(b *Board, x, y int) int {
+	b.CountNeighbors()
+	return b.Cells[x][y].Neighbors()
+}
+
+func TestTwist(t *testing.T) {
+	tests := []struct {
+		name  string
+		twist int
+		live  [2]int
+		cell  [2]int
+		want  int
+	}{
+		// Wrapping right from the last row lands twist columns up.
+		{"across the right edge", 2, [2]int{0, 3}, [2]int{3, 1}, 1},
+		{"across the right edge untwisted", 0, [2]int{0, 3}, [2]int{3, 1}, 0},
+
+		// And wrapping left lands twist columns down, so the seam is the
+		// same both ways.
+		{"across the left edge", 2, [2]int{3, 1}, [2]int{0, 3}, 1},
+		{"across the left edge untwisted", 0, [2]int{3, 1}, [2]int{0, 3}, 0},
+
+		// The shift wraps around the columns too.
+		{"past the top", 2, [2]int{0, 1}, [2]int{3, 5}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBoard(4, 6, conway)
+			b.Twist = tt.twist
+			b.Cells[tt.live[0]][tt.live[1]].Set(true)
+			if n := neighbors(b, tt.cell[0], tt.cell[1]); n != tt.want {
+				t.Errorf("cell %v has %d live neighbors, want %d", tt.cell, n, tt.want)
+			}
+		})
+	}
+}
+
+func TestCornerWrap(t *testing.T) {
+	// Each corner of a 4x4 board and the opposite corner, its diagonal
+	// neighbor only by wrapping both ways.
+	corners := [][2][2]int{
+		{{0, 0}, {3, 3}},
+		{{3, 0}, {0, 3}},
+		{{0, 3}, {3, 0}},
+		{{3, 3}, {0, 0}},
+	}
+
+	for _, cornerWrap := range []bool{true, false} {
+		for _, c := range corners {
+			b := NewBoard(4, 4, conway)
+			b.CornerWrap = cornerWrap
+			b.Cells[c[1][0]][c[1][1]].Set(true)
+
+			want := 0
+			if cornerWrap {
+				want = 1
+			}
+			if n := neighbors(b, c[0][0], c[0][1]); n != want {
+				t.Errorf("corner wrap %v: corner %v has %d live neighbors, want %d", cornerWrap, c[0], n, want)
+			}
+		}
+	}
+}
+
+func TestCornerWrapKeepsEdges(t *testing.T) {
+	b := NewBoard(4, 4, conway)
+	b.CornerWrap = false
+	b.Cells[3][0].Set(true)
+	b.Cells[0][3].Set(true)
+	if n := neighbors(b, 0, 0); n != 2 {
+		t.Errorf("corner has %d live neighbors across the edges, want 2", n)
+	}
+}
+
+func TestStepSimultaneous(t *testing.T) {
+	// Were any cell to see a neighbor already moved on, the blinker would
+	// lose its shape within a few generations.
+	phases := []*Board{
+		newTestBoard(10, 10, blinker, 0, 0),
+		newTestBoard(10, 10, points{{3, 4}, {4, 4}, {5, 4}}, 0, 0),
+	}
+
+	b := newTestBoard(10, 10, blinker, 0, 0)
+	for i := 1; i <= 10; i++ {
+		b.Step()
+		checkAlive(t, b, phases[i%2])
+		if births, deaths := b.Changes(); births != 2 || deaths != 2 {
+			t.Errorf("generation %d: %d births and %d deaths, want 2 and 2", i, births, deaths)
+		}
+	}
+}
+
+func TestRectangularBoard(t *testing.T) {
+	b := NewBoard(3, 5, conway)
+	if b.Rows() != 3 || b.Columns() != 5 {
+		t.Fatalf("board is %dx%d, want 3x5", b.Rows(), b.Columns())
+	}
+
+	// On a torus each corner touches the other three, across an edge or
+	// both.
+	corners := points{{0, 0}, {2, 0}, {0, 4}, {2, 4}}
+	setAlive(b, corners, 0, 0)
+	for _, c := range corners {
+		if n := neighbors(b, c[0], c[1]); n != 3 {
+			t.Errorf("corner %v has %d live neighbors, want 3", c, n)
+		}
+	}
+	if n := neighbors(b, 1, 2); n != 0 {
+		t.Errorf("middle has %d live neighbors, want 0", n)
+	}
+
+	b.Step()
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		wrap bool
+		want int
+	}{
+		{true, 3},
+
+		// Only the neighbor on the board itself counts.
+		{false, 1},
+	}
+
+	for _, tt := range tests {
+		b := NewBoard(4, 4, conway)
+		b.Wrap = tt.wrap
+		setAlive(b, points{{3, 3}, {0, 1}, {3, 0}}, 0, 0)
+		if n := neighbors(b, 0, 0); n != tt.want {
+			t.Errorf("wrap %v: corner has %d live neighbors, want %d", tt.wrap, n, tt.want)
+		}
+	}
+}
+
+func TestBriansBrain(t *testing.T) {
+	r, err := ParseRule("B2/S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewBoard(8, 8, r)
+	b.States = 3
+	setAlive(b, points{{3, 3}, {3, 4}}, 0, 0)
+
+	// Nothing survives, so the pair starts dying, while the four cells
+	// touching both of them are born.
+	b.Step()
+	born := points{{2, 3}, {2, 4}, {4, 3}, {4, 4}}
+	checkAlive(t, b, newTestBoard(8, 8, born, 0, 0))
+	for _, p := range (points{{3, 3}, {3, 4}}) {
+		if d := b.Cells[p[0]][p[1]].Dying(); d != 1 {
+			t.Errorf("cell %v dying for %d generations, want 1", p, d)
+		}
+	}
+
+	// The pair in the middle is fully dead after one generation of decay,
+	// and wasn't born again despite its live neighbors.
+	b.Step()
+	for _, p := range (points{{3, 3}, {3, 4}}) {
+		if c := b.Cells[p[0]][p[1]]; c.Alive() || c.Dying() != 0 {
+			t.Errorf("cell %v alive %v and dying %d, want fully dead", p, c.Alive(), c.Dying())
+		}
+	}
+	for _, p := range born {
+		if d := b.Cells[p[0]][p[1]].Dying(); d != 1 {
+			t.Errorf("cell %v dying for %d generations, want 1", p, d)
+		}
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	// A 3x3 square with the middle at 2, 2, and a cell on the edge of it.
+	square := points{{1, 1}, {1, 2}, {1, 3}, {2, 1}, {2, 2}, {2, 3}, {3, 1}, {3, 2}, {3, 3}}
+
+	tests := []struct {
+		neighborhood Neighborhood
+		middle, edge int
+	}{
+		{Moore, 8, 5},
+		{VonNeumann, 4, 3},
+	}
+
+	for _, tt := range tests {
+		b := newTestBoard(5, 5, square, 0, 0)
+		b.Neighborhood = tt.neighborhood
+		if n := neighbors(b, 2, 2); n != tt.middle {
+			t.Errorf("neighborhood %d: middle has %d live neighbors, want %d", tt.neighborhood, n, tt.middle)
+		}
+		if n := neighbors(b, 1, 2); n != tt.edge {
+			t.Errorf("neighborhood %d: edge has %d live neighbors, want %d", tt.neighborhood, n, tt.edge)
+		}
+	}
+}
+
+func TestHexagonal(t *testing.T) {
+	tests := []struct {
+		name string
+		cell [2]int
+		want points
+	}{
+		// Rows with even y sit half a cell back from those with odd y,
+		// so their neighbors above and below lean the other way.
+		{"even", [2]int{2, 2}, points{{1, 2}, {3, 2}, {1, 3}, {2, 3}, {1, 1}, {2, 1}}},
+		{"odd", [2]int{2, 3}, points{{1, 3}, {3, 3}, {2, 4}, {3, 4}, {2, 2}, {3, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every neighbor counts, one at a time, and nothing else
+			// in the ring of eight around the cell does.
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					x, y := tt.cell[0]+dx, tt.cell[1]+dy
+					if x == tt.cell[0] && y == tt.cell[1] {
+						continue
+					}
+
+					b := newTestBoard(6, 6, points{{x, y}}, 0, 0)
+					b.Neighborhood = Hexagonal
+
+					want := 0
+					for _, p := range tt.want {
+						if p == [2]int{x, y} {
+							want = 1
+						}
+					}
+					if n := neighbors(b, tt.cell[0], tt.cell[1]); n != want {
+						t.Errorf("with %d,%d alive cell has %d live neighbors, want %d", x, y, n, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// pattern returns p as a pattern for Place, indexed [x][y].
+func pattern(p points) [][]bool {
+	var rows, columns int
+	for _, pt := range p {
+		if pt[0] >= rows {
+			rows = pt[0] + 1
+		}
+		if pt[1] >= columns {
+			columns = pt[1] + 1
+		}
+	}
+
+	cells := make([][]bool, rows)
+	for x := range cells {
+		cells[x] = make([]bool, columns)
+	}
+	for _, pt := range p {
+		cells[pt[0]][pt[1]] = true
+	}
+	return cells
+}
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrap    bool
+		clipped int
+		want    points
+	}{
+		{"wrapped", true, 0, points{{9, 8}, {0, 9}, {8, 0}, {9, 0}, {0, 0}}},
+		{"clipped", false, 4, points{{9, 8}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBoard(10, 10, conway)
+			if clipped := b.Place(pattern(glider), 8, 8, tt.wrap); clipped != tt.clipped {
+				t.Errorf("Place clipped %d cells, want %d", clipped, tt.clipped)
+			}
+			checkAlive(t, b, newTestBoard(10, 10, tt.want, 0, 0))
+		})
+	}
+}
+
+func TestPlaceKeepsLiveCells(t *testing.T) {
+	b := newTestBoard(10, 10, block, 0, 0)
+	for i := 0; i < 3; i++ {
+		b.Step()
+	}
+
+	// Placing over live cells leaves them be, ages and all.
+	b.Place(pattern(block), 2, 2, true)
+	if age := b.Cells[2][2].Age(); age != 3 {
+		t.Errorf("cell under the pattern has age %d, want 3", age)
+	}
+}
+
+func TestToggle(t *testing.T) {
+	b := NewBoard(10, 10, conway)
+	b.Toggle(0, 0)
+	if !b.Alive(0, 0) {
+		t.Error("toggling a dead cell left it dead")
+	}
+	b.Toggle(0, 0)
+	if b.Alive(0, 0) {
+		t.Error("toggling a live cell left it alive")
+	}
+}
+
+// cellState is everything Step changes about a cell.
+type cellState struct {
+	alive                   bool
+	unchanged, age, deadFor int
+	dying, team             int
+	energy                  float64
+}
+
+// stepWith returns the state of every cell on a random board with every
+// feature Step handles turned on, after 20 steps with GOMAXPROCS at procs.
+func stepWith(procs int) [][]cellState {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+
+	b := randomBoard(97, 61)
+	b.States = 4
+	b.Immigration = true
+	b.Ecosystem = true
+	b.EnergyRegen, b.EnergyCost = 0.05, 0.5
+	for x := range b.Cells {
+		for y, c := range b.Cells[x] {
+			c.SetTeam((x + y) % 2)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		b.Step()
+	}
+
+	states := make([][]cellState, b.Rows())
+	for x := range b.Cells {
+		for _, c := range b.Cells[x] {
+			states[x] = append(states[x], cellState{c.alive, c.unchanged, c.age, c.deadFor, c.dying, c.team, c.energy})
+		}
+	}
+	return states
+}
+
+func TestStepBands(t *testing.T) {
+	// Splitting the board into bands mustn't change a thing.
+	serial := stepWith(1)
+	for _, procs := range []int{2, 3, 8} {
+		parallel := stepWith(procs)
+		for x := range serial {
+			for y := range serial[x] {
+				if parallel[x][y] != serial[x][y] {
+					t.Fatalf("%d bands: cell %d,%d is %+v, want %+v as in one", procs, x, y, parallel[x][y], serial[x][y])
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkStepBands steps a 500x500 board in as many bands as GOMAXPROCS
+// allows. Run it with -cpu 1,2,4 to see how Step scales with cores.
+func BenchmarkStepBands(b *testing.B) {
+	board := randomBoard(500, 500)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.Step()
+	}
+}
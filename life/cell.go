@@ -0,0 +1,289 @@
+package life
+
+import (
+	"math"
+)
+
+// Cell is one position on a Board.
+type Cell struct {
+	// Color is the color the cell is drawn in. The board never looks at it.
+	Color [4]float32
+
+	// State is the continuous state in [0,1] used by smooth rules in place of
+	// alive. Step leaves it alone.
+	State float64
+
+	alive     bool
+	aliveNext bool
+
+	// ruleOverride marks the cell as a wild card: while it's alive, the
+	// cells around it go by the board's OverrideRule. It stays with the
+	// cell through births and deaths.
+	ruleOverride bool
+
+	// unchanged counts the generations since alive last changed.
+	unchanged int
+
+	// age counts the generations the cell has stayed alive since it was
+	// born. It's zero while the cell is dead.
+	age int
+
+	// deadFor counts the generations since the cell last died in a step. It's
+	// zero while the cell is alive, or if it hasn't died since it was set.
+	deadFor int
+
+	// dying counts the generations since the cell died while it decays on a
+	// Generations board, and is zero otherwise.
+	dying     int
+	dyingNext int
+
+	// team is which of two teams the cell belongs to on an immigration
+	// board, 0 or 1. It only means anything while the cell is alive.
+	team     int
+	teamNext int
+
+	// energy is what's left at this position for births on an ecosystem
+	// board, from 0 to 1.
+	energy float64
+
+	// neighbors is the live neighbor count recorded by CountNeighbors.
+	neighbors int
+
+	x int
+	y int
+
+	// board is the board the cell is on, told when the cell is changed by
+	// hand so it knows its flat grid is out of date.
+	board *Board
+}
+
+func newCell(b *Board, x, y int) *Cell {
+	return &Cell{
+		energy: 1,
+
+		x:     x,
+		y:     y,
+		board: b,
+	}
+}
+
+// Alive reports whether the cell is alive in the current generation.
+func (c *Cell) Alive() bool {
+	return c.alive
+}
+
+// Set makes the cell alive or dead by hand, outside of a step. The cell
+// counts as newly changed afterwards.
+func (c *Cell) Set(alive bool) {
+	c.alive = alive
+	c.aliveNext = alive
+	c.unchanged = 0
+	c.age = 0
+	c.deadFor = 0
+	c.dying = 0
+	c.dyingNext = 0
+	c.board.flat.stale = true
+}
+
+// Unchanged returns the number of generations since the cell last changed.
+func (c *Cell) Unchanged() int {
+	return c.unchanged
+}
+
+// Age returns the number of generations the cell has stayed alive since it
+// was born, or zero if it's dead.
+func (c *Cell) Age() int {
+	return c.age
+}
+
+// DeadFor returns how many generations ago the cell died, or zero if it's
+// alive or hasn't died since it was last set by hand.
+func (c *Cell) DeadFor() int {
+	return c.deadFor
+}
+
+// Dying returns how many generations ago the cell died if it's still
+// decaying on a Generations board, or zero if it's alive or fully dead.
+func (c *Cell) Dying() int {
+	return c.dying
+}
+
+// Team returns which of the two teams on an immigration board the cell
+// belongs to, 0 or 1.
+func (c *Cell) Team() int {
+	return c.team
+}
+
+// SetTeam puts the cell on team 0 or 1 by hand.
+func (c *Cell) SetTeam(team int) {
+	c.team = team
+	c.teamNext = team
+	c.board.flat.stale = true
+}
+
+// RuleOverride reports whether the cell is a wild card, whose neighbors go by
+// the board's OverrideRule while it's alive.
+func (c *Cell) RuleOverride() bool {
+	return c.ruleOverride
+}
+
+// SetRuleOverride makes the cell a wild card, or an ordinary cell again.
+func (c *Cell) SetRuleOverride(on bool) {
+	if on == c.ruleOverride {
+		return
+	}
+	c.ruleOverride = on
+	if on {
+		c.board.overrides++
+	} else {
+		c.board.overrides--
+	}
+	c.board.flat.stale = true
+}
+
+// Neighbors returns how many live neighbors the cell had when the board's
+// CountNeighbors was last called.
+func (c *Cell) Neighbors() int {
+	return c.neighbors
+}
+
+// Energy returns how much energy the cell's position has left for births on
+// an ecosystem board, from 0 to 1.
+func (c *Cell) Energy() float64 {
+	return c.energy
+}
+
+// checkState determines the state of the cell for the next tick of the game
+// under the board's rule. It only reads the current state of the board,
+// leaving the result in aliveNext until commit.
+//
+// Under Conway's B3/S23 a live cell with two or three live neighbors lives on
+// and any other live cell dies, of underpopulation or overpopulation, while a
+// dead cell with exactly three live neighbors comes alive by reproduction.
+//
+// On a Generations board a live cell that doesn't survive decays through the
+// board's extra states before it's fully dead. A decaying cell isn't a live
+// neighbor and can't be born again until it's done.
+//
+// A cell next to a live wild card goes by the board's OverrideRule, whatever
+// rule it would go by otherwise.
+func (c *Cell) checkState(b *Board) {
+	if b.Ecosystem {
+		c.regenerate(b)
+	}
+
+	c.dyingNext = 0
+	if c.dying > 0 {
+		c.aliveNext = false
+		if c.dying+1 < b.States-1 {
+			c.dyingNext = c.dying + 1
+		}
+		return
+	}
+
+	liveCount, teamOne := c.liveNeighbors(b)
+	r := &b.Rule
+	if len(b.Zones) > 0 || b.MorphGenerations > 0 || b.overrides > 0 {
+		r = c.rule(b)
+	}
+	c.teamNext = c.team
+	if c.alive {
+		c.aliveNext = r.survive[liveCount]
+		if !c.aliveNext && b.States > 2 {
+			c.dyingNext = 1
+		}
+	} else {
+		// On an ecosystem board a birth also needs the energy for it.
+		c.aliveNext = r.birth[liveCount] && c.fundBirth(b)
+
+		// On an immigration board a newborn joins the team most of its
+		// parents are on, with ties going to team 0.
+		if c.aliveNext && b.Immigration {
+			c.teamNext = 0
+			if 2*teamOne > liveCount {
+				c.teamNext = 1
+			}
+		}
+	}
+}
+
+// commit moves the cell on to the state checkState determined for it.
+func (c *Cell) commit() {
+	if c.alive == c.aliveNext {
+		c.unchanged++
+	} else {
+		c.unchanged = 0
+	}
+	if c.alive && c.aliveNext {
+		c.age++
+	} else {
+		c.age = 0
+	}
+	switch {
+	case c.alive && !c.aliveNext:
+		c.deadFor = 1
+	case c.aliveNext:
+		c.deadFor = 0
+	case c.deadFor > 0:
+		c.deadFor++
+	}
+	c.alive = c.aliveNext
+	c.dying = c.dyingNext
+	c.team = c.teamNext
+}
+
+// liveNeighbors returns the number of live neighbors for a cell within the
+// board's neighborhood, and how many of them are on team 1. It reads the
+// board's flat grid, so that has to be synced first.
+func (c *Cell) liveNeighbors(b *Board) (liveCount, teamOne int) {
+	g := &b.flat
+	i := g.index(c.x, c.y)
+	for _, o := range g.offsets[c.y&1] {
+		liveCount += int(g.alive[i+o])
+		teamOne += int(g.teamOne[i+o])
+	}
+	return liveCount, teamOne
+}
+
+// rule returns the rule the cell goes by in this step: the board's
+// OverrideRule next to a live wild card, or else whatever ruleAt gives for its
+// position.
+func (c *Cell) rule(b *Board) *Rule {
+	if b.overrides > 0 && c.nearOverride(b) {
+		return &b.OverrideRule
+	}
+	return b.ruleAt(c.x, c.y)
+}
+
+// nearOverride reports whether any of the cell's neighbors is a live wild
+// card. Like liveNeighbors it reads the board's flat grid.
+func (c *Cell) nearOverride(b *Board) bool {
+	g := &b.flat
+	i := g.index(c.x, c.y)
+	for _, o := range g.offsets[c.y&1] {
+		if g.override[i+o] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// regenerate lets the cell's position recover some energy, up to full.
+func (c *Cell) regenerate(b *Board) {
+	c.energy = math.Min(1, c.energy+b.EnergyRegen)
+}
+
+// fundBirth reports whether a birth can happen at the cell and, on an
+// ecosystem board, pays for it out of the position's energy. Births are free
+// on every other board.
+func (c *Cell) fundBirth(b *Board) bool {
+	if !b.Ecosystem {
+		return true
+	}
+	if c.energy < b.EnergyCost {
+		return false
+	}
+
+	c.energy -= b.EnergyCost
+	return true
+}
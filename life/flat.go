@@ -0,0 +1,159 @@
+package life
+
+// flatGrid is a copy of which cells on a board are alive, laid out in one
+// flat slice for counting neighbors. Walking the Cells means chasing a pointer
+// per neighbor, while here every neighbor is a fixed distance away in memory.
+//
+// The grid has a halo one cell wide around the board, filled in with whatever
+// each position beyond the edge stands for: the cell it wraps around to, or
+// nothing on a bounded board. Counting never has to think about the edges.
+type flatGrid struct {
+	// stride is the distance between neighboring rows, the board's columns
+	// plus the halo on either side. Cell x, y is at (x+1)*stride + y+1.
+	stride int
+
+	// alive holds 1 for each live cell, teamOne 1 for each live cell on
+	// team 1, and override 1 for each live wild card. override is nil on a
+	// board without wild cards, to save keeping it up to date.
+	alive    []uint8
+	teamOne  []uint8
+	override []uint8
+
+	// offsets are the distances to a cell's neighbors under the board's
+	// neighborhood, for cells with even and odd y.
+	offsets [2][]int
+
+	// stale is set when cells have been changed by hand since the grid was
+	// last synced, so it no longer matches them. Step keeps it up to date
+	// itself as it commits each generation.
+	stale bool
+}
+
+// index returns where the cell at x, y is in the flat slices. x and y can be
+// one past the edges of the board, into the halo.
+func (g *flatGrid) index(x, y int) int {
+	return (x+1)*g.stride + y + 1
+}
+
+// set copies the cell c into the grid at index i.
+func (g *flatGrid) set(i int, c *Cell) {
+	g.alive[i], g.teamOne[i] = 0, 0
+	if c.alive {
+		g.alive[i] = 1
+		g.teamOne[i] = uint8(c.team)
+	}
+	if g.override != nil {
+		g.override[i] = 0
+		if c.alive && c.ruleOverride {
+			g.override[i] = 1
+		}
+	}
+}
+
+// sync brings the flat grid up to date with the board, resizing it if the
+// board has changed size. It must run before any cell's neighbors are
+// counted. The cells themselves are only copied if the grid is stale; the
+// offsets and the halo depend on the board's settings, which can change at
+// any time, so they're always redone.
+func (b *Board) sync() {
+	g := &b.flat
+	rows, columns := b.Rows(), b.Columns()
+
+	size := (rows + 2) * (columns + 2)
+	if len(g.alive) != size || g.stride != columns+2 {
+		g.stride = columns + 2
+		g.alive = make([]uint8, size)
+		g.teamOne = make([]uint8, size)
+		g.override = nil
+		g.stale = true
+	}
+	if (b.overrides > 0) != (g.override != nil) {
+		g.override = nil
+		if b.overrides > 0 {
+			g.override = make([]uint8, size)
+		}
+		g.stale = true
+	}
+
+	for parity := range g.offsets {
+		g.offsets[parity] = g.offsets[parity][:0]
+		for _, o := range b.Neighborhood.offsets(parity) {
+			g.offsets[parity] = append(g.offsets[parity], o[0]*g.stride+o[1])
+		}
+	}
+
+	if g.stale {
+		for x := range b.Cells {
+			for _, c := range b.Cells[x] {
+				g.set(g.index(c.x, c.y), c)
+			}
+		}
+		g.stale = false
+	}
+
+	// Fill in the halo, down both long sides and then across the ends
+	// including the corners.
+	for x := 0; x < rows; x++ {
+		b.syncHalo(x, -1)
+		b.syncHalo(x, columns)
+	}
+	for y := -1; y <= columns; y++ {
+		b.syncHalo(-1, y)
+		b.syncHalo(rows, y)
+	}
+}
+
+// syncHalo copies into the halo position x, y the cell it stands for.
+func (b *Board) syncHalo(x, y int) {
+	g := &b.flat
+	i := g.index(x, y)
+	g.alive[i], g.teamOne[i] = 0, 0
+	if g.override != nil {
+		g.override[i] = 0
+	}
+
+	if sx, sy, ok := b.wrapped(x, y); ok {
+		g.set(i, b.Cells[sx][sy])
+	}
+}
+
+// wrapped returns the cell that a position just beyond the edge of the board
+// stands for. ok is false if it stands for nothing, and is always dead.
+func (b *Board) wrapped(x, y int) (wx, wy int, ok bool) {
+	// x runs over the rows of the board and y over its columns.
+	rows, columns := b.Rows(), b.Columns()
+
+	outsideX := x < 0 || x >= rows
+	outsideY := y < 0 || y >= columns
+
+	// With bounded edges there's nothing beyond the board, so those
+	// neighbors are always dead.
+	if !b.Wrap && (outsideX || outsideY) {
+		return 0, 0, false
+	}
+
+	// Only a diagonal from a corner can be off the board on both axes.
+	// Without corner wrap that neighbor is treated as dead, while edges
+	// still wrap as usual.
+	if !b.CornerWrap && outsideX && outsideY {
+		return 0, 0, false
+	}
+
+	// If we're at an edge, check the other side of the board. On a twisted
+	// torus, wrapping across the left or right edge also shifts the row by
+	// the twist.
+	if x == rows {
+		x = 0
+		y += b.Twist
+	} else if x == -1 {
+		x = rows - 1
+		y -= b.Twist
+	}
+
+	y %= columns
+	if y < 0 {
+		y += columns
+	}
+
+	return x, y, true
+}
@@ -0,0 +1,149 @@
+package life
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// cellNeighbors counts the live neighbors of the cell at x, y by walking the
+// Cells, the way the board did before it had a flat grid.
+func cellNeighbors(b *Board, x, y int) int {
+	var count int
+	for _, o := range b.Neighborhood.offsets(y) {
+		nx, ny := x+o[0], y+o[1]
+		if nx < 0 || nx >= b.Rows() || ny < 0 || ny >= b.Columns() {
+			var ok bool
+			if nx, ny, ok = b.wrapped(nx, ny); !ok {
+				continue
+			}
+		}
+		if b.Cells[nx][ny].alive {
+			count++
+		}
+	}
+	return count
+}
+
+// checkFlat fails t unless counting from the flat grid agrees with walking the
+// cells for every cell on b.
+func checkFlat(t *testing.T, b *Board) {
+	t.Helper()
+	b.CountNeighbors()
+	for x := range b.Cells {
+		for y, c := range b.Cells[x] {
+			if want := cellNeighbors(b, x, y); c.Neighbors() != want {
+				t.Fatalf("cell %d,%d has %d live neighbors in the flat grid, want %d", x, y, c.Neighbors(), want)
+			}
+		}
+	}
+}
+
+func TestFlatGrid(t *testing.T) {
+	tests := []struct {
+		name         string
+		wrap         bool
+		cornerWrap   bool
+		twist        int
+		neighborhood Neighborhood
+	}{
+		{"torus", true, true, 0, Moore},
+		{"bounded", false, true, 0, Moore},
+		{"no corner wrap", true, false, 0, Moore},
+		{"twisted", true, true, 3, Moore},
+		{"von neumann", true, true, 0, VonNeumann},
+		{"hexagonal", true, true, 0, Hexagonal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := randomBoard(23, 17)
+			b.Wrap = tt.wrap
+			b.CornerWrap = tt.cornerWrap
+			b.Twist = tt.twist
+			b.Neighborhood = tt.neighborhood
+			checkFlat(t, b)
+
+			// Stepping keeps the grid up to date by itself, and cells
+			// set by hand in between are picked up.
+			r := rand.New(rand.NewSource(2))
+			for i := 0; i < 10; i++ {
+				b.Step()
+				checkFlat(t, b)
+				b.Cells[r.Intn(b.Rows())][r.Intn(b.Columns())].Set(true)
+				checkFlat(t, b)
+			}
+		})
+	}
+}
+
+func TestFlatGridGrow(t *testing.T) {
+	b := NewBoard(10, 10, conway)
+	b.Wrap = false
+	setAlive(b, glider, 0, 0)
+	checkFlat(t, b)
+
+	if grew, _ := b.Grow(2, 100, 100); !grew {
+		t.Fatal("board didn't grow")
+	}
+	checkFlat(t, b)
+	b.Step()
+	checkFlat(t, b)
+}
+
+func TestEachLive(t *testing.T) {
+	b := randomBoard(31, 19)
+	for i := 0; i < 5; i++ {
+		b.Step()
+		b.Toggle(i, i)
+
+		var want, got [][2]int
+		for x := range b.Cells {
+			for y, c := range b.Cells[x] {
+				if c.Alive() {
+					want = append(want, [2]int{x, y})
+				}
+			}
+		}
+		b.EachLive(func(x, y int, c *Cell) {
+			if c != b.Cells[x][y] {
+				t.Fatalf("EachLive passed the wrong cell for %d,%d", x, y)
+			}
+			got = append(got, [2]int{x, y})
+		})
+
+		if len(got) != len(want) {
+			t.Fatalf("EachLive found %d live cells, want %d", len(got), len(want))
+		}
+		for j := range got {
+			if got[j] != want[j] {
+				t.Fatalf("EachLive's cell %d is %v, want %v", j, got[j], want[j])
+			}
+		}
+	}
+}
+
+// BenchmarkNeighbors compares counting every cell's neighbors from the flat
+// grid with walking the cells. Counting from the flat grid is spread across
+// CPUs, so run it with -cpu 1 to compare like with like.
+func BenchmarkNeighbors(b *testing.B) {
+	board := randomBoard(500, 500)
+
+	b.Run("flat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			board.flat.stale = true
+			board.CountNeighbors()
+		}
+	})
+
+	b.Run("cells", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for x := range board.Cells {
+				for y, c := range board.Cells[x] {
+					c.neighbors = cellNeighbors(board, x, y)
+				}
+			}
+		}
+	})
+}
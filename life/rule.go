@@ -0,0 +1,77 @@
+package life
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is a life-like rule in B/S notation: the live neighbor counts at which
+// a dead cell is born, and at which a live cell survives.
+type Rule struct {
+	birth   [9]bool
+	survive [9]bool
+}
+
+// ParseRule parses a rulestring such as "B3/S23" (Conway's Life) or
+// "B36/S23" (HighLife). The B and S parts may come in either order, letters
+// may be either case, and either set may be empty, as in "B2/S".
+func ParseRule(s string) (Rule, error) {
+	var r Rule
+
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return r, fmt.Errorf("invalid rule %q: want the form B3/S23", s)
+	}
+
+	var haveBirth, haveSurvive bool
+	for _, part := range parts {
+		if part == "" {
+			return r, fmt.Errorf("invalid rule %q: want the form B3/S23", s)
+		}
+
+		var counts *[9]bool
+		switch part[0] {
+		case 'B', 'b':
+			counts = &r.birth
+			haveBirth = true
+		case 'S', 's':
+			counts = &r.survive
+			haveSurvive = true
+		default:
+			return r, fmt.Errorf("invalid rule %q: %q should start with B or S", s, part)
+		}
+
+		for _, ch := range part[1:] {
+			if ch < '0' || ch > '8' {
+				return r, fmt.Errorf("invalid rule %q: neighbor count %q isn't between 0 and 8", s, ch)
+			}
+			counts[ch-'0'] = true
+		}
+	}
+
+	if !haveBirth || !haveSurvive {
+		return r, fmt.Errorf("invalid rule %q: needs both a B and an S part", s)
+	}
+	return r, nil
+}
+
+// String returns the rule in B/S notation.
+func (r Rule) String() string {
+	var b strings.Builder
+
+	b.WriteByte('B')
+	for n, ok := range r.birth {
+		if ok {
+			b.WriteByte(byte('0' + n))
+		}
+	}
+
+	b.WriteString("/S")
+	for n, ok := range r.survive {
+		if ok {
+			b.WriteByte(byte('0' + n))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package life
+
+import (
+	"testing"
+)
+
+// counts returns the neighbor counts set in c.
+func counts(c [9]bool) []int {
+	var set []int
+	for n, ok := range c {
+		if ok {
+			set = append(set, n)
+		}
+	}
+	return set
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		birth   []int
+		survive []int
+		str     string
+	}{
+		{"B3/S23", []int{3}, []int{2, 3}, "B3/S23"},
+		{"B36/S23", []int{3, 6}, []int{2, 3}, "B36/S23"},
+		{"B3678/S34678", []int{3, 6, 7, 8}, []int{3, 4, 6, 7, 8}, "B3678/S34678"},
+		{"B2/S", []int{2}, nil, "B2/S"},
+		{"s23/b3", []int{3}, []int{2, 3}, "B3/S23"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRule(tt.rule)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := counts(r.birth); !equalInts(got, tt.birth) {
+			t.Errorf("ParseRule(%q) births at %v, want %v", tt.rule, got, tt.birth)
+		}
+		if got := counts(r.survive); !equalInts(got, tt.survive) {
+			t.Errorf("ParseRule(%q) survives at %v, want %v", tt.rule, got, tt.survive)
+		}
+		if r.String() != tt.str {
+			t.Errorf("ParseRule(%q).String() = %q, want %q", tt.rule, r.String(), tt.str)
+		}
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	for _, s := range []string{"", "B3", "B9/S23", "X3/S2", "B3/B2", "B3/S2/x", "/S23"} {
+		if _, err := ParseRule(s); err == nil {
+			t.Errorf("ParseRule(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"log"
+)
+
+// logLevel is how much gets logged, each level including the ones before it.
+type logLevel int
+
+const (
+	// levelError logs things that went wrong but didn't stop the run.
+	levelError logLevel = iota
+
+	// levelWarn adds settings that couldn't be honored in full.
+	levelWarn
+
+	// levelInfo adds what the run is doing, such as the seed and files
+	// saved.
+	levelInfo
+
+	// levelDebug adds a line for every generation.
+	levelDebug
+)
+
+var (
+	quiet       = flag.Bool("quiet", false, "only log errors")
+	verbose     = flag.Bool("v", false, "log what the run is doing, such as the seed and OpenGL version, as well as warnings and errors")
+	veryVerbose = flag.Bool("vv", false, "log everything -v does and a line for every generation")
+)
+
+// verbosity returns the level set by -quiet, -v and -vv, logging warnings
+// and errors by default.
+func verbosity() logLevel {
+	switch {
+	case *quiet:
+		return levelError
+	case *veryVerbose:
+		return levelDebug
+	case *verbose:
+		return levelInfo
+	}
+	return levelWarn
+}
+
+// logf logs a message, formatted as by log.Printf, if verbosity is at least
+// level.
+func logf(level logLevel, format string, v ...interface{}) {
+	if level <= verbosity() {
+		log.Printf(format, v...)
+	}
+}
+
+func logErrorf(format string, v ...interface{}) { logf(levelError, format, v...) }
+func logWarnf(format string, v ...interface{})  { logf(levelWarn, format, v...) }
+func logInfof(format string, v ...interface{})  { logf(levelInfo, format, v...) }
+func logDebugf(format string, v ...interface{}) { logf(levelDebug, format, v...) }
+
+// logResultf logs an outcome of the run, such as the board dying out or an
+// oscillator found with -detect-period. Like a warning, it's shown unless
+// -quiet is set.
+func logResultf(format string, v ...interface{}) { logf(levelWarn, format, v...) }
@@ -1,33 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
-	"math/rand"
+	"image"
+	"math"
+	"os"
+	"os/signal"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aculler/conway-gol/life"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
 const (
-	width  = 500
-	height = 500
-
-	rows    = 50
-	columns = 50
-
-	threshold = 0.15
-	fps       = 10
-
 	vertexShaderSource = `
 		#version 410
 		in vec3 vp;
 
+		uniform mat4 view;
+
 		void main() {
-			gl_Position = vec4(vp, 1.0);
+			gl_Position = view * vec4(vp, 1.0);
 		}
 ` + "\x00"
 
@@ -43,6 +43,91 @@ const (
 ` + "\x00"
 )
 
+const (
+	modeLife      = "life"
+	modeSmooth    = "smooth"
+	modeSpacetime = "spacetime"
+	modeEcosystem = "ecosystem"
+
+	seedStyleUniform = "uniform"
+	seedStyleCluster = "cluster"
+	seedStyleRadial  = "radial"
+	seedStyleNoise   = "noise"
+
+	neighborhoodMoore      = "moore"
+	neighborhoodVonNeumann = "vonneumann"
+
+	windowTitle = "Conway's Game of Life"
+
+	// titleInterval is the least time between updates of the generation and
+	// population shown in the title bar.
+	titleInterval = 250 * time.Millisecond
+
+	// minFPS and maxFPS bound the speed set with the + and - keys.
+	minFPS = 1
+	maxFPS = 120
+
+	// manualIdle is the longest the loop waits for input with -manual before
+	// drawing again, when there's nothing to step.
+	manualIdle = 0.25
+
+	// clusterDensity is the chance of each cell inside a cluster seed's blob
+	// starting alive.
+	clusterDensity = 0.5
+)
+
+var (
+	width  = flag.Int("width", 500, "window width in pixels")
+	height = flag.Int("height", 500, "window height in pixels")
+
+	rows    = flag.Int("rows", 50, "number of rows in the grid")
+	columns = flag.Int("columns", 50, "number of columns in the grid")
+
+	threshold = flag.Float64("threshold", 0.15, "chance of each cell starting alive, from 0 to 1")
+	fps       = flag.Int("fps", 10, "generations per second")
+	seed      = flag.Int64("seed", 0, "seed for the starting board and colors (defaults to the current time)")
+
+	ruleString = flag.String("rule", "B3/S23", "life-like rule in B/S notation, such as B36/S23 for HighLife")
+	states     = flag.Int("states", 2, "cell states, for Generations rules: above 2, dead cells decay through the extra states, as in Brian's Brain (-rule B2/S -states 3)")
+
+	neighborhood = flag.String("neighborhood", neighborhoodMoore, "cells counted as neighbors: moore (all eight around a cell) or vonneumann (the four orthogonal ones)")
+
+	immigration = flag.Bool("immigration", false, "split live cells into two teams, each newborn joining the team most of its parents are on")
+
+	patternFile = flag.String("pattern", "", "start from an RLE, Life 1.06 or plaintext pattern file, centered on the grid, instead of a random board (- reads standard input)")
+
+	mode   = flag.String("mode", modeLife, "simulation mode: life, smooth, spacetime or ecosystem")
+	hidden = flag.Bool("hidden", false, "create the window hidden, for rendering without a visible display")
+	msaa   = flag.Int("msaa", 4, "samples per pixel for multisample anti-aliasing, smoothing the edges of cells (0 turns it off)")
+	twist  = flag.Int("twist", 0, "rows to shift by when wrapping across the left or right edge (twisted torus)")
+
+	headless = flag.Bool("headless", false, "run without a window, printing each generation to stdout as text")
+	fast     = flag.Bool("fast", false, "with -headless, step as fast as possible instead of at -fps")
+
+	wrap       = flag.Bool("wrap", true, "wrap around the edges of the board (a torus); when false, cells beyond the edges are dead")
+	cornerWrap = flag.Bool("cornerwrap", true, "let diagonal neighbors of corner cells wrap to the opposite corner")
+
+	bpm          = flag.Float64("bpm", 0, "step in time with this many beats per minute instead of at a steady fps")
+	subdivisions = flag.Int("subdivisions", 1, "generations to step per beat when -bpm is set")
+
+	seedStyle   = flag.String("seedstyle", seedStyleUniform, "initial seeding: uniform noise, scattered dense clusters, radial (densest in the center) or noise (smooth patches of varying density)")
+	blobSize    = flag.Int("blobsize", 4, "width and height of each blob with -seedstyle cluster")
+	blobSpacing = flag.Int("blobspacing", 12, "spacing between blobs with -seedstyle cluster")
+	noiseScale  = flag.Int("noisescale", 10, "size in cells of the patches with -seedstyle noise")
+
+	boardCode = flag.String("code", "", "start from a board code printed by pressing C")
+
+	focusActive = flag.Bool("focusactive", false, "dim cells that haven't changed recently")
+	focusAfter  = flag.Int("focusafter", 20, "generations a cell must stay unchanged before -focusactive dims it")
+
+	trails = flag.Int("trails", 0, "generations that cells which died stay visible for, fading out (0 turns trails off)")
+
+	exitOnDeath = flag.Bool("exit-on-death", false, "close the window and exit once every cell has died")
+	generations = flag.Int("generations", 0, "exit after stepping this many generations (0 runs until closed)")
+
+	manual = flag.Bool("manual", false, "never step on a timer, only one generation each time N is pressed")
+)
+
 var (
 	square = []float32{
 		-0.5, 0.5, 0,
@@ -55,152 +140,958 @@ var (
 	}
 )
 
-type cell struct {
-	drawable uint32
+// drawColor returns the color to draw the cell in. ok is false if the cell
+// should be left blank.
+func drawColor(c *life.Cell) (color [4]float32, ok bool) {
+	brightness := float32(1)
+	if *mode == modeSmooth {
+		// Smooth cells fade with their state rather than switching on and off.
+		if c.State < 0.01 {
+			return color, false
+		}
+		brightness = float32(c.State)
+	} else if d := c.Dying(); d > 0 {
+		// Decaying cells fade out over the extra states.
+		brightness = 1 - float32(d)/float32(*states-1)
+	} else if !c.Alive() {
+		if t := c.DeadFor(); t > 0 && t <= *trails {
+			// Cells that died recently leave a trail that fades out.
+			brightness = 1 - float32(t)/float32(*trails+1)
+		} else if *mode == modeEcosystem {
+			return energyColor(c), true
+		} else {
+			return color, false
+		}
+	}
 
-	color [4]float32
+	// Settled cells fade into the background so the eye goes to the churn.
+	if *focusActive && c.Unchanged() >= *focusAfter {
+		brightness *= 0.25
+	}
 
-	alive     bool
-	aliveNext bool
+	// -color, -immigration, -agecolors and -color-by-neighbors can't be
+	// combined, and smooth cells keep their own colors whichever is set.
+	// Live wild cards stand out from all of them.
+	base := c.Color
+	switch {
+	case *mode == modeSmooth:
+	case c.RuleOverride() && c.Alive():
+		base = wildCardColor
+	case *solidColorString != "":
+		base = solidColor
+	case *immigration:
+		base = teamColors[c.Team()]
+	case *ageColors:
+		base = ageColor(c.Age())
+	case *colorByNeighbors && c.Alive():
+		base = neighborColor(c.Neighbors())
+	}
 
-	x int
-	y int
+	return [4]float32{base[0] * brightness, base[1] * brightness, base[2] * brightness, base[3]}, true
 }
 
-// checkState determines the state of the cell for the next tick of the game.
-func (c *cell) checkState(cells [][]*cell) {
-	c.alive = c.aliveNext
-	c.aliveNext = c.alive
+// onlyLiveDrawn reports whether drawColor leaves every dead cell blank, as it
+// does unless dead cells leave trails, decay, or show their state or energy.
+func onlyLiveDrawn() bool {
+	return (*mode == modeLife || *mode == modeSpacetime) && *trails == 0 && *states == 2
+}
 
-	liveCount := c.liveNeighbors(cells)
-	if c.alive {
-		// 1. Any live cell with fewer than two live neighbors dies, as if caused by underpopulation
-		if liveCount < 2 {
-			c.aliveNext = false
+func main() {
+	flag.Parse()
+	if err := loadSettings(flag.CommandLine, *configFile, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *hexGrid && !isFlagSet("rule") {
+		*ruleString = hexRule
+	}
+	if err := checkFlags(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *dumpConfigFile != "" {
+		if err := saveConfig(*dumpConfigFile); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to save config:", err)
+			os.Exit(1)
 		}
+		logInfof("Saved config to %s", *dumpConfigFile)
+	}
 
-		// 2. Any live cell with two or three live neighbors lives on to the next generation.
-		if liveCount == 2 || liveCount == 3 {
-			c.aliveNext = true
+	activeRule, err := life.ParseRule(*ruleString)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if activeRule, err = parseMorphRules(activeRule); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := parseColorFlags(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var pattern [][]bool
+	if *methuselahName != "" {
+		pattern = mustParseRLE(methuselahs[*methuselahName].rle)
+	}
+	if *patternFile != "" {
+		var err error
+		if pattern, err = loadPattern(*patternFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if pattern, err = fitPattern(pattern, *rows, *columns, *oversized); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	// The image is kept for -imageseed-interactive to sample again.
+	var seedImage image.Image
+	if *imageFile != "" {
+		var err error
+		if seedImage, err = readImage(*imageFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		pattern = imageToBoard(seedImage, *rows, *columns, *imageThreshold, *imageFit)
+	}
 
-		// 3. Any live cell with more than three live neighbors dies, as if by overpopulation
-		if liveCount > 3 {
-			c.aliveNext = false
+	if *wildCards != "" {
+		var err error
+		if overrideRule, err = life.ParseRule(*wildRule); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -wildrule:", err)
+			flag.Usage()
+			os.Exit(2)
 		}
-	} else {
-		// 4. Any dead cell with exactly three live neighbors becomes a live cell, as if by reproduction
-		if liveCount == 3 {
-			c.aliveNext = true
+		if wildCardPattern, err = loadPattern(*wildCards); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
 	}
-}
 
-// liveNeighbors returns the number of live neighbors for a cell
-func (c *cell) liveNeighbors(cells [][]*cell) int {
-	var liveCount int
-	add := func(x, y int) {
-		// If we're at an edge, check the other side of the board.
-		if x == len(cells) {
-			x = 0
-		} else if x == -1 {
-			x = len(cells) - 1
+	var watched [][]bool
+	if *watchFor != "" {
+		var err error
+		if watched, err = loadPattern(*watchFor); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+	}
+
+	var codeBoard [][]bool
+	if *boardCode != "" {
+		var err error
+		if codeBoard, err = decodeBoard(*boardCode); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
 
-		if y == len(cells[x]) {
-			y = 0
-		} else if y == -1 {
-			y = len(cells[x]) - 1
+	cellSeed := time.Now().UnixNano()
+	if isFlagSet("seed") {
+		cellSeed = *seed
+	}
+	logInfof("Seed %d", cellSeed)
+
+	rules, err := parseBoardRules(activeRule)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	zones, err := parseZoneRules(*zoneRules, *rows, *columns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	boards := make([]*life.Board, numBoards())
+	for i := range boards {
+		boards[i] = makeTiledBoard(i, cellSeed, pattern, rules[i], zones)
+		if codeBoard != nil {
+			if err := applyBoard(boards[i].Cells, codeBoard); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
 		}
+	}
+
+	var kernel *smoothKernel
+	if *mode == modeSmooth {
+		kernel = newSmoothKernel(*smoothRadius)
+	}
 
-		if cells[x][y].alive {
-			liveCount++
+	// Ctrl-C ends the run the same way closing the window does, so the
+	// deferred cleanup still runs. GL has to stay on the locked thread, so
+	// nothing happens on the signal itself; the loop notices ctx is done.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		// Once the first Ctrl-C is caught, a second one kills the process as
+		// usual in case shutting down hangs.
+		<-ctx.Done()
+		stop()
+	}()
+
+	var stats *statsWriter
+	if *statsFile != "" {
+		if stats, err = newStatsWriter(*statsFile); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create stats file:", err)
+			os.Exit(1)
 		}
+		defer stats.close()
 	}
 
-	add(c.x-1, c.y)   // To the left
-	add(c.x+1, c.y)   // To the right
-	add(c.x, c.y+1)   // Up
-	add(c.x, c.y-1)   // Down
-	add(c.x-1, c.y+1) // Top-left
-	add(c.x+1, c.y+1) // Top-right
-	add(c.x-1, c.y-1) // Bottom-left
-	add(c.x+1, c.y-1) // Bottom-right
+	// The events are written however the run ends, short of a failure.
+	var events *eventLog
+	if *eventsFile != "" {
+		if events, err = newEventLog(*eventsFile); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create events file:", err)
+			os.Exit(1)
+		}
+		defer events.close()
+	}
 
-	return liveCount
-}
+	var collector *statsCollector
+	if *httpAddr != "" {
+		collector = newStatsCollector()
+		if err := serveHTTP(*httpAddr, collector); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to start stats server:", err)
+			os.Exit(1)
+		}
+	}
 
-func (c *cell) draw(program uint32) {
-	if !c.alive {
-		return
+	games := make([]*Game, len(boards))
+	for i, board := range boards {
+		var growth *grower
+		if *grow {
+			growth = newGrower(cellSeed + int64(i))
+		}
+		warmUp(ctx, board, kernel, growth)
+		games[i] = newGame(board, kernel, growth, stats, events)
+		if collector != nil {
+			games[i].collect(collector)
+		}
+		if watched != nil {
+			// Each board looks for its own first match.
+			games[i].watchFor(newWatcher(watched))
+		}
 	}
 
-	vertexColorLocation := gl.GetUniformLocation(program, gl.Str("squareColor\x00"))
-	gl.Uniform4f(vertexColorLocation, c.color[0], c.color[1], c.color[2], c.color[3])
+	// The first board is the one the keyboard edits and the graph follows.
+	g := games[0]
+	cells := g.board.Cells
 
-	gl.BindVertexArray(c.drawable)
-	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(square)/3))
-}
+	if *methuselahName != "" {
+		runMethuselah(ctx, g, *methuselahName, os.Stdout)
+		return
+	}
+	if *svgAnimFile != "" {
+		if err := runSVGAnim(ctx, g, *svgAnimFile); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to save SVG animation:", err)
+			os.Exit(1)
+		}
+		logInfof("Saved %d generations to %s", *svgGens, *svgAnimFile)
+		return
+	}
+	if *headless {
+		runHeadless(ctx, g, os.Stdout)
+		return
+	}
 
-func main() {
 	runtime.LockOSThread()
 
-	window := initGlfw()
+	window := initGlfw(*width, *height)
 	defer glfw.Terminate()
 
-	program := initOpenGL()
+	// os.Exit skips the deferred calls, so glfw is shut down by hand before
+	// giving up.
+	fail := func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+		glfw.Terminate()
+		os.Exit(1)
+	}
+
+	program, colorLocation, err := initOpenGL()
+	if err != nil {
+		fail(err)
+	}
+	defer gl.DeleteProgram(program)
+
+	// Each board has its own renderer, and grid lines, as each can grow to
+	// a different size.
+	for _, gm := range games {
+		if gm.renderer, err = newCellRenderer(*rows, *columns); err != nil {
+			fail(err)
+		}
+		defer gm.renderer.delete()
+
+		if *gridLinesEnabled {
+			gm.lines = newGridLines(*rows, *columns)
+			defer gm.lines.delete()
+		}
+		if zones != nil {
+			gm.zones = newZoneLines(zones, *rows, *columns)
+			defer gm.zones.delete()
+		}
+	}
 
-	cells := makeCells()
-	for !window.ShouldClose() {
-		t := time.Now()
+	var st *spacetime
+	if *mode == modeSpacetime {
+		if st, err = newSpacetime(g.renderer, *spacetimeLayers, float32(*width)/float32(*height)); err != nil {
+			fail(err)
+		}
+		defer st.delete()
+		st.attach(window)
+		st.record(cells)
+	}
 
-		for x := range cells {
-			for _, c := range cells[x] {
-				c.checkState(cells)
+	// Keep track of the window's size when it's resized or goes fullscreen,
+	// for placing the boards in it, and keep the spacetime view's aspect
+	// ratio matched to it.
+	var fbWidth, fbHeight int
+	resize := func(w, h int) {
+		// A minimized window has no size to draw at.
+		if w == 0 || h == 0 {
+			return
+		}
+		fbWidth, fbHeight = w, h
+		if st != nil {
+			_, _, vw, vh := gridViewport(w, h, *rows, *columns)
+			st.aspect = float32(vw) / float32(vh)
+		}
+	}
+	resize(window.GetFramebufferSize())
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+		resize(width, height)
+	})
+	var windowed windowPlacement
+
+	// gridView pans and zooms the grid, though not the spacetime view,
+	// which has its own camera.
+	gridView := newView()
+	viewLocation := gl.GetUniformLocation(program, gl.Str("view\x00"))
+
+	cur := newCursor(*rows, *columns)
+	defer cur.delete()
+	pop := newGraph()
+	defer pop.delete()
+	picker := newColorPicker()
+	defer picker.delete()
+	pop.record(population(g.board))
+
+	var titleUpdated time.Time
+
+	// brush is stamped by clicking, or nil to toggle single cells instead.
+	brush := brushes[*brushName]
+
+	// While paused, or always with -manual, the board only advances when a
+	// single step is requested.
+	// -imageseed-interactive starts paused for the threshold to be tuned.
+	var paused, stepRequested, screenshotRequested bool
+	paused = *imageSeedInteractive
+
+	// -countdown holds the starting board until countdownEnd, or until the
+	// first key, which does nothing else.
+	counting := *countdown > 0
+	countdownEnd := time.Now().Add(time.Duration(*countdown) * time.Second)
+	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		if action == glfw.Release {
+			return
+		}
+		if counting {
+			counting = false
+			return
+		}
+		// While picking a color the arrow keys adjust it.
+		if picker.key(key, mods) {
+			return
+		}
+
+		switch key {
+		case glfw.KeySpace:
+			if action == glfw.Press {
+				paused = !paused
+			}
+		case glfw.KeyN:
+			if paused || *manual {
+				stepRequested = true
+			}
+		case glfw.KeyUp, glfw.KeyDown, glfw.KeyLeft, glfw.KeyRight:
+			var dx, dy int
+			switch key {
+			case glfw.KeyUp:
+				dy = 1
+			case glfw.KeyDown:
+				dy = -1
+			case glfw.KeyLeft:
+				dx = -1
+			case glfw.KeyRight:
+				dx = 1
+			}
+
+			// Shift pans the view instead of moving the cursor.
+			if mods&glfw.ModShift != 0 {
+				gridView.pan(float32(dx)*panStep, float32(dy)*panStep)
+			} else {
+				cur.move(cells, dx, dy)
+			}
+		case glfw.KeyHome:
+			gridView.reset()
+		case glfw.KeyEnter:
+			if action == glfw.Press {
+				cur.toggle(g.board)
+			}
+		case glfw.KeyC:
+			if action == glfw.Press {
+				code, err := encodeBoard(cells)
+				if err != nil {
+					logErrorf("%v", err)
+					return
+				}
+				fmt.Println(code)
+			}
+		case glfw.KeyS:
+			if action == glfw.Press {
+				path := fmt.Sprintf("board-%s.cells", time.Now().Format("20060102-150405"))
+				if err := saveBoard(cells, path); err != nil {
+					logErrorf("Failed to save board: %v", err)
+					return
+				}
+				logInfof("Saved board to %s", path)
+			}
+		case glfw.KeyV:
+			if action == glfw.Press {
+				if *hexGrid {
+					logErrorf("Can't save hexagons as SVG, it only draws squares")
+					return
+				}
+				path := fmt.Sprintf("board-%s.svg", time.Now().Format("20060102-150405"))
+				if err := saveSVG(cells, path); err != nil {
+					logErrorf("Failed to save SVG: %v", err)
+					return
+				}
+				logInfof("Saved board to %s", path)
+			}
+		case glfw.KeyP:
+			// The frame can only be read back between drawing it and
+			// swapping, which -manual's wait for input doesn't fall
+			// between, so the loop takes the screenshot after it next
+			// draws.
+			if action == glfw.Press {
+				screenshotRequested = true
+			}
+		case glfw.KeyB:
+			if action == glfw.Press {
+				before := snapshot(cells)
+				if centered, ok := centerPattern(before, *hexGrid); ok {
+					g.undo = before
+					restore(cells, centered)
+				}
+			}
+		case glfw.KeyZ:
+			if action == glfw.Press && g.undo != nil {
+				restore(cells, g.undo)
+				g.undo = nil
+			}
+		case glfw.KeyG:
+			if action == glfw.Press {
+				pop.visible = !pop.visible
+			}
+		case glfw.KeyK:
+			if action != glfw.Press {
+				return
+			}
+			if *solidColorString == "" {
+				logWarnf("The color picker adjusts -color, which isn't set")
+				return
+			}
+			picker.toggle()
+			if picker.active {
+				return
+			}
+			logInfof("Picked -color %s (%s)", *solidColorString, hexColor(solidColor))
+			// A config dumped at the start is brought up to date with
+			// the color picked.
+			if *dumpConfigFile != "" {
+				if err := saveConfig(*dumpConfigFile); err != nil {
+					logErrorf("Failed to save config: %v", err)
+					return
+				}
+				logInfof("Saved config to %s", *dumpConfigFile)
+			}
+		case glfw.KeyF11:
+			if action == glfw.Press {
+				toggleFullscreen(w, &windowed)
+			}
+		case glfw.KeyR:
+			// Start over from a new random board, whatever the board
+			// started from.
+			if action == glfw.Press {
+				resetSeed := time.Now().UnixNano()
+				logInfof("Seed %d", resetSeed)
+
+				for i, gm := range games {
+					board := makeTiledBoard(i, resetSeed, nil, rules[i], zones)
+					warmUp(ctx, board, kernel, gm.growth)
+					gm.reset(board)
+				}
+				cells = g.board.Cells
+
+				pop.reset()
+				pop.record(population(g.board))
+				if st != nil {
+					st.reset()
+					st.record(cells)
+				}
+			}
+		case glfw.Key0, glfw.Key1, glfw.Key2, glfw.Key3, glfw.Key4:
+			if action != glfw.Press {
+				return
+			}
+			if key == glfw.Key0 {
+				brush = nil
+				logInfof("Clicking toggles cells")
+				return
+			}
+			if i := int(key - glfw.Key1); i < len(brushNames) {
+				brush = brushes[brushNames[i]]
+				logInfof("Clicking stamps a %s", brushNames[i])
+			}
+		case glfw.KeyLeftBracket, glfw.KeyRightBracket:
+			// The board is sampled from the image again at the new
+			// threshold, until it first steps.
+			if !*imageSeedInteractive || g.generation > 0 {
+				return
+			}
+			steps := 1
+			if key == glfw.KeyLeftBracket {
+				steps = -1
+			}
+			*imageThreshold = stepThreshold(*imageThreshold, steps)
+
+			pattern := imageToBoard(seedImage, *rows, *columns, *imageThreshold, *imageFit)
+			for i, gm := range games {
+				gm.reset(makeTiledBoard(i, cellSeed, pattern, rules[i], zones))
+			}
+			cells = g.board.Cells
+
+			pop.reset()
+			pop.record(population(g.board))
+			if st != nil {
+				st.reset()
+				st.record(cells)
+			}
+			titleUpdated = time.Time{}
+		case glfw.KeyEqual, glfw.KeyKPAdd:
+			if *fps < maxFPS {
+				*fps++
+			}
+		case glfw.KeyMinus, glfw.KeyKPSubtract:
+			if *fps > minFPS {
+				*fps--
 			}
 		}
-		draw(cells, window, program)
+	})
+
+	// Clicking toggles cells while paused, or stamps the brush at any time.
+	// The spacetime view uses the mouse to orbit instead.
+	if st == nil {
+		window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+			if button != glfw.MouseButtonLeft || action != glfw.Press || (brush == nil && !paused) {
+				return
+			}
+
+			// The cursor position is in window coordinates, from the top
+			// left, while the viewport is placed from the bottom left.
+			px, py := w.GetCursorPos()
+			width, height := w.GetSize()
+			i, ok := tileAt(px, py, len(games), width, height)
+			if !ok {
+				return
+			}
+			board := games[i].board
+			vx, vy, vw, vh := boardViewport(i, len(games), width, height, board.Rows(), board.Columns())
+			top := height - vy - vh
+			position := gridPosition
+			if *hexGrid {
+				position = hexGridPosition
+			}
+			gx, gy := gridView.unprojectWindow(px-float64(vx), py-float64(top), vw, vh)
+			x, y, ok := position(gx, gy, vw, vh, board.Rows(), board.Columns())
+			if !ok {
+				return
+			}
+
+			if brush != nil {
+				stampBrush(board, brush, x, y)
+			} else {
+				toggle(board, x, y)
+			}
+		})
+
+		// Scrolling zooms in and out around the mouse. Every board shares
+		// the view.
+		window.SetScrollCallback(func(w *glfw.Window, xoff, yoff float64) {
+			px, py := w.GetCursorPos()
+			width, height := w.GetSize()
+			i, ok := tileAt(px, py, len(games), width, height)
+			if !ok {
+				return
+			}
+			board := games[i].board
+			vx, vy, vw, vh := boardViewport(i, len(games), width, height, board.Rows(), board.Columns())
+			top := height - vy - vh
 
-		time.Sleep(time.Second/time.Duration(fps) - time.Since(t))
+			x, y := toNDC(px-float64(vx), py-float64(top), vw, vh)
+			gridView.zoom(float32(math.Pow(zoomStep, yoff)), x, y)
+		})
 	}
-}
 
-func makeCells() [][]*cell {
-	rand.Seed(time.Now().UnixNano())
+	var rec *recorder
+	if *recordFile != "" {
+		rec = newRecorder(*recordFile, *recordFrames, frameInterval())
+	}
 
-	cells := make([][]*cell, rows, columns)
-	for x := 0; x < rows; x++ {
-		for y := 0; y < columns; y++ {
-			c := newCell(x, y)
+	var pace pacer
+	for !window.ShouldClose() && ctx.Err() == nil {
+		if counting && countdownLeft(countdownEnd, time.Now()) == 0 {
+			counting = false
+		}
 
-			c.alive = rand.Float64() < threshold
-			c.aliveNext = c.alive
+		var advanced bool
+		if (!paused && !*manual && !counting) || stepRequested {
+			for _, gm := range games {
+				if gm.Step() {
+					advanced = true
+				}
+			}
+		}
 
-			var min float32
-			min = 0.2
-			genColor := func() float32 {
-				c := rand.Float32()
-				if c < min {
-					c = min
+		// Finding the -watchfor pattern pauses on it, which can be
+		// on the starting board.
+		for _, gm := range games {
+			if gm.spotted {
+				paused, gm.spotted = true, false
+			}
+		}
+		if advanced {
+			// The board is replaced when it grows.
+			cells = g.board.Cells
+
+			// The run is over once any board has stepped -generations
+			// times, or once every board has died. A stable board stops
+			// counting, so a run that's waiting on a count, or on
+			// -record's frames, is also over once every board has
+			// settled, as it is headless.
+			finished, extinct, settled := false, true, true
+			for _, gm := range games {
+				finished = finished || (*generations > 0 && gm.generation >= *generations)
+				extinct = extinct && gm.extinct
+				settled = settled && gm.stable != nil
+			}
+			if settled && (*generations > 0 || rec != nil) {
+				finished = true
+			}
+			if finished {
+				// The frame is still drawn, and recorded, before the
+				// window closes.
+				window.SetShouldClose(true)
+			}
+			if extinct && *exitOnDeath {
+				window.SetShouldClose(true)
+			}
+			if st != nil {
+				st.record(cells)
+			}
+			pop.record(population(g.board))
+		}
+		stepRequested = false
+
+		// Manual steps come slowly enough to show every one.
+		if time.Since(titleUpdated) >= titleInterval || (*manual && advanced) {
+			speed := fmt.Sprintf("%d fps", *fps)
+			switch {
+			case *manual:
+				speed = "press N to step"
+			case *bpm > 0:
+				speed = fmt.Sprintf("%g bpm", *bpm)
+			}
+			// The boards step in lockstep, but a stable one stops
+			// counting, so the generation shown is the furthest along.
+			generation := 0
+			pops := make([]string, len(games))
+			for i, gm := range games {
+				if gm.generation > generation {
+					generation = gm.generation
 				}
-				return c
+				pops[i] = strconv.Itoa(population(gm.board))
+				if *compareTopology {
+					pops[i] = topologyLabels[i] + " " + pops[i]
+				}
+			}
+			title := fmt.Sprintf("%s — gen %d, pop %s, %s", windowTitle, generation, strings.Join(pops, "/"), speed)
+			if *imageSeedInteractive {
+				title += fmt.Sprintf(", threshold %.2f", *imageThreshold)
+			}
+			if *morphGens > 0 {
+				title += fmt.Sprintf(", morph %.2f", g.board.MorphBlend())
+			}
+			if counting {
+				title += fmt.Sprintf(", starting in %d", countdownLeft(countdownEnd, time.Now()))
 			}
+			if picker.active {
+				title += fmt.Sprintf(", color %s (arrows for hue and value, Shift for saturation, K when done)", hexColor(solidColor))
+			}
+			window.SetTitle(title)
+			titleUpdated = time.Now()
+		}
+
+		// The first board changes size when it grows, or is reset after
+		// growing, and the cursor has to follow it.
+		if rows, columns := g.board.Rows(), g.board.Columns(); rows != cur.rows || columns != cur.columns {
+			cur.resize(rows, columns)
+		}
 
-			c.color = [4]float32{
-				genColor(),
-				genColor(),
-				genColor(),
-				1,
+		draw(games, cur, st, pop, picker, gridView, fbWidth, fbHeight, program, colorLocation, viewLocation)
+		if screenshotRequested {
+			path := fmt.Sprintf("screenshot-%s.png", time.Now().Format("20060102-150405"))
+			if err := saveScreenshot(fbWidth, fbHeight, path); err != nil {
+				logErrorf("Failed to save screenshot: %v", err)
+			} else {
+				logInfof("Saved screenshot to %s", path)
 			}
+			screenshotRequested = false
+		}
+		if rec != nil && advanced && !rec.done() {
+			fbWidth, fbHeight := window.GetFramebufferSize()
+			if rec.capture(fbWidth, fbHeight) {
+				window.SetShouldClose(true)
+			}
+		}
 
-			cells[x] = append(cells[x], c)
+		// With -manual there's no timer to keep, so the loop sleeps until
+		// there's input, waking now and then in case anything else needs
+		// drawing.
+		if *manual {
+			window.SwapBuffers()
+			glfw.WaitEventsTimeout(manualIdle)
+			continue
 		}
+
+		glfw.PollEvents()
+		window.SwapBuffers()
+
+		time.Sleep(pace.delay(time.Now(), frameInterval()))
 	}
 
-	return cells
+	if ctx.Err() != nil {
+		logInfof("Interrupted")
+	}
+
+	// A recording cut short by closing the window still keeps what it has.
+	if rec != nil && len(rec.anim.Image) > 0 {
+		if err := rec.save(); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to save recording:", err)
+			os.Exit(1)
+		}
+		logInfof("Saved recording to %s", *recordFile)
+	}
+}
+
+// checkFlags validates the command line flags, returning an error describing
+// the first bad value.
+func checkFlags() error {
+	switch {
+	case *width < 1 || *height < 1:
+		return errors.New("-width and -height must be positive")
+	case *rows < 1 || *columns < 1:
+		return errors.New("-rows and -columns must be positive")
+	case *threshold < 0 || *threshold > 1:
+		return errors.New("-threshold must be between 0 and 1")
+	case *fps < 1:
+		return errors.New("-fps must be positive")
+	}
+
+	switch *mode {
+	case modeLife, modeSmooth, modeSpacetime, modeEcosystem:
+	default:
+		return fmt.Errorf("invalid -mode %q", *mode)
+	}
+	if *neighborhood != neighborhoodMoore && *neighborhood != neighborhoodVonNeumann {
+		return fmt.Errorf("invalid -neighborhood %q", *neighborhood)
+	}
+	switch *seedStyle {
+	case seedStyleUniform, seedStyleCluster, seedStyleRadial, seedStyleNoise:
+	default:
+		return fmt.Errorf("invalid -seedstyle %q", *seedStyle)
+	}
+
+	switch {
+	case *energyRegen < 0 || *energyCost < 0:
+		return errors.New("-energy-regen and -energy-cost must not be negative")
+	case *focusAfter < 1:
+		return errors.New("-focusafter must be at least 1")
+	case *blobSize < 1 || *blobSpacing < 1:
+		return errors.New("-blobsize and -blobspacing must be at least 1")
+	case *noiseScale < 1:
+		return errors.New("-noisescale must be at least 1")
+	case *spacetimeLayers < 1:
+		return errors.New("-spacetime-layers must be at least 1")
+	case *bpm < 0 || *subdivisions < 1:
+		return errors.New("-bpm must not be negative and -subdivisions must be at least 1")
+	case *immigration && *ageColors:
+		return errors.New("-immigration and -agecolors both color the cells, use one or the other")
+	case *colorByNeighbors && (*immigration || *ageColors):
+		return errors.New("-color-by-neighbors, -immigration and -agecolors all color the cells, use only one")
+	case *solidColorString != "" && (*immigration || *ageColors || *colorByNeighbors || *paletteString != paletteRandom || *mode == modeSmooth):
+		return errors.New("-color gives every live cell the same color, so it can't be combined with -palette, -immigration, -agecolors, -color-by-neighbors or -mode smooth")
+	case *colorByNeighbors && *mode == modeSmooth:
+		return errors.New("-color-by-neighbors doesn't work with -mode smooth, whose cells don't simply live and die")
+	case *brushName != "" && brushes[*brushName] == nil:
+		return fmt.Errorf("unknown -brush %q", *brushName)
+	case *patternFile != "" && *imageFile != "":
+		return errors.New("-pattern and -image both set the starting board, use one or the other")
+	case *imageThreshold < 0 || *imageThreshold > 1:
+		return errors.New("-image-threshold must be between 0 and 1")
+	case *imageFit != imageFitLetterbox && *imageFit != imageFitStretch:
+		return fmt.Errorf("invalid -image-fit %q", *imageFit)
+	case *imageSeedInteractive && (*imageFile == "" || *headless || *warmup > 0):
+		return errors.New("-imageseed-interactive tunes -image in a window before the board steps, so it needs -image and can't be combined with -headless or -warmup")
+	case *zoneRules != "" && (*grow || *mode == modeSmooth):
+		return errors.New("-zonerules doesn't work with -grow, which would move the zones' cells, or -mode smooth, which has no B/S rule")
+	case *morphGens < 0:
+		return errors.New("-morphgens must not be negative")
+	case (*ruleTo != "") != (*morphGens > 0):
+		return errors.New("-ruleto and -morphgens go together, one says what to morph to and the other how long it takes")
+	case *ruleFrom != "" && (*ruleTo == "" || *boardRules != ""):
+		return errors.New("-rulefrom is the rule -ruleto morphs from, so it needs -ruleto and can't be combined with -board-rules")
+	case *ruleTo != "" && *mode == modeSmooth:
+		return errors.New("-ruleto doesn't work with -mode smooth, which has no B/S rule")
+	case *wildCards != "" && *mode == modeSmooth:
+		return errors.New("-wildcards doesn't work with -mode smooth, which has no B/S rule")
+	case *watchFor != "" && *mode == modeSmooth:
+		return errors.New("-watchfor doesn't work with -mode smooth, whose cells don't simply live and die")
+	case *methuselahName != "" && methuselahs[*methuselahName].rle == "":
+		return fmt.Errorf("unknown -methuselah %q", *methuselahName)
+	case *methuselahName != "" && (*patternFile != "" || *imageFile != "" || *boardCode != ""):
+		return errors.New("-methuselah sets the starting board, leave out -pattern, -image and -code")
+	case *methuselahName != "" && (*mode != modeLife || *states != 2 || *warmup > 0 || numBoards() > 1):
+		return errors.New("-methuselah runs one board of plain -mode life from its first generation, leave out -states, -warmup, -boards and -compare-topology")
+	case *oversized != oversizedError && *oversized != oversizedCrop && *oversized != oversizedScale:
+		return fmt.Errorf("invalid -oversized %q", *oversized)
+	case *grow && (*mode == modeSmooth || *mode == modeSpacetime):
+		return errors.New("-grow doesn't work with -mode smooth or spacetime")
+	case *grow && (*growMax < *rows || *growMax < *columns):
+		return errors.New("-grow-max must be at least -rows and -columns")
+	case !validAlign(*align):
+		return fmt.Errorf("invalid -align %q", *align)
+	case *cellSize < 0:
+		return errors.New("-cell-size must not be negative")
+	case *statsFile != "" && *mode == modeSmooth:
+		return errors.New("-stats doesn't work with -mode smooth, whose cells don't simply live and die")
+	case *mode == modeSmooth && *smoothRadius < 1:
+		return errors.New("-smooth-radius must be at least 1")
+	case *mode == modeSmooth && (*smoothAlphaN <= 0 || *smoothAlphaM <= 0):
+		return errors.New("-smooth-alphan and -smooth-alpham must be positive")
+	case *mode == modeSmooth && *smoothDt <= 0:
+		return errors.New("-smooth-dt must be positive")
+	case *msaa < 0 || *msaa > 16:
+		return errors.New("-msaa must be between 0 and 16")
+	case *generations < 0:
+		return errors.New("-generations must not be negative")
+	case *hexGrid && isFlagSet("neighborhood"):
+		return errors.New("-hex sets its own neighborhood, leave out -neighborhood")
+	case *hexGrid && *gridLinesEnabled:
+		return errors.New("-gridlines only works on square grids, not with -hex")
+	case *hexGrid && (*wrap || *compareTopology) && !*grow && (*columns%2 == 1 || *twist%2 != 0):
+		return errors.New("-hex needs even -columns and -twist to wrap around")
+	case *trails < 0:
+		return errors.New("-trails must not be negative")
+	case *detectPeriod < 0:
+		return errors.New("-detect-period must not be negative")
+	case *detectSpaceship < 0:
+		return errors.New("-detect-spaceship must not be negative")
+	case *boardCount < 1:
+		return errors.New("-boards must be at least 1")
+	case numBoards() > 1 && (*headless || *mode == modeSpacetime || *statsFile != "" || *eventsFile != "" || *httpAddr != ""):
+		return errors.New("-boards and -compare-topology can't be combined with -headless, -mode spacetime, -stats, -events or -http, which follow a single board")
+	case *compareTopology && *grow:
+		return errors.New("-grow turns off wrapping, which leaves -compare-topology nothing to compare")
+	case *states < 2:
+		return errors.New("-states must be at least 2")
+	case *maxAge < 1:
+		return errors.New("-maxage must be at least 1")
+	case *recordFrames < 1 || *recordFrames > maxRecordFrames:
+		return fmt.Errorf("-frames must be between 1 and %d", maxRecordFrames)
+	case *outlineWidth <= 0:
+		return errors.New("-outline-width must be positive")
+	case *warmup < 0:
+		return errors.New("-warmup must not be negative")
+	case *countdown < 0:
+		return errors.New("-countdown must not be negative")
+	case *countdown > 0 && (*headless || *manual):
+		return errors.New("-countdown holds the board in a window before it starts stepping by itself, so it can't be combined with -headless or -manual")
+	case *svgGens < 1 || *svgGens > maxSVGGens:
+		return fmt.Errorf("-svggens must be between 1 and %d", maxSVGGens)
+	case *svgAnimFile != "" && (*mode != modeLife || *hexGrid || *grow || numBoards() > 1):
+		return errors.New("-svganim draws one board of square cells that live or die, so it needs -mode life and can't be combined with -hex, -grow, -boards or -compare-topology")
+	case *svgAnimFile != "" && (*headless || *methuselahName != ""):
+		return errors.New("-svganim runs without a window by itself, leave out -headless and -methuselah")
+	case *manual && *headless:
+		return errors.New("-manual steps on key presses, so it needs a window, not -headless")
+	case *quiet && (*verbose || *veryVerbose):
+		return errors.New("-quiet and -v or -vv ask for opposite things, use one or the other")
+	}
+
+	return nil
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(name string) bool {
+	return isSet(flag.CommandLine, name)
 }
 
-func newCell(x, y int) *cell {
+// isSet reports whether the named flag in fs has been set.
+func isSet(fs *flag.FlagSet, name string) bool {
+	var set bool
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// frameInterval returns how long each generation should last: one subdivision
+// of a beat when -bpm is set, otherwise one frame at fps.
+func frameInterval() time.Duration {
+	if *bpm > 0 {
+		return time.Duration(float64(time.Minute) / (*bpm * float64(*subdivisions)))
+	}
+	return time.Second / time.Duration(*fps)
+}
+
+// stepBoard advances the board one generation, under the smooth kernel with
+// -mode smooth.
+func stepBoard(board *life.Board, kernel *smoothKernel) {
+	if *mode == modeSmooth {
+		stepSmooth(board, kernel)
+	} else {
+		board.Step()
+	}
+}
+
+// cellPoints returns the square's vertices moved and scaled to the position of
+// the cell at x, y on a rows by columns grid.
+func cellPoints(x, y, rows, columns int) []float32 {
 	points := make([]float32, len(square), len(square))
 	copy(points, square)
 
@@ -208,12 +1099,13 @@ func newCell(x, y int) *cell {
 		var position float32
 		var size float32
 
+		// x counts rows across the window and y counts columns up it.
 		switch i % 3 {
 		case 0:
-			size = 1.0 / float32(columns)
+			size = 1.0 / float32(rows)
 			position = float32(x) * size
 		case 1:
-			size = 1.0 / float32(rows)
+			size = 1.0 / float32(columns)
 			position = float32(y) * size
 		default:
 			continue
@@ -226,26 +1118,40 @@ func newCell(x, y int) *cell {
 		}
 	}
 
-	return &cell{
-		drawable: makeVao(points),
+	return points
+}
 
-		x: x,
-		y: y,
+// draw draws every board into its part of the fbWidth by fbHeight window, with
+// the cursor, graph and color picker swatch over the first.
+func draw(games []*Game, cur *cursor, st *spacetime, pop *graph, picker *colorPicker, gridView *view, fbWidth, fbHeight int, program uint32, colorLocation, viewLocation int32) {
+	if len(games) > 1 {
+		clearTiles(len(games), fbWidth, fbHeight)
+	} else {
+		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	}
-}
 
-func draw(cells [][]*cell, window *glfw.Window, program uint32) {
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-	gl.UseProgram(program)
+	m := gridView.matrix()
+	for i, g := range games {
+		cells := g.board.Cells
+		x, y, w, h := boardViewport(i, len(games), fbWidth, fbHeight, len(cells), len(cells[0]))
+		gl.Viewport(int32(x), int32(y), int32(w), int32(h))
 
-	for x := range cells {
-		for _, c := range cells[x] {
-			c.draw(program)
+		if st != nil {
+			st.draw(cells)
+			continue
 		}
-	}
+		g.Render(m, program, colorLocation, viewLocation)
+		if i > 0 {
+			continue
+		}
+		cur.draw(colorLocation)
 
-	glfw.PollEvents()
-	window.SwapBuffers()
+		// The graph stays put over the board however the grid is viewed.
+		id := identity()
+		gl.UniformMatrix4fv(viewLocation, 1, false, &id[0])
+		pop.draw(colorLocation)
+		picker.draw(colorLocation)
+	}
 }
 
 func compileShader(source string, shaderType uint32) (uint32, error) {
@@ -260,29 +1166,44 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
 	if status == gl.FALSE {
 		var logLength int32
-		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &status)
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
 
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+		return 0, fmt.Errorf("failed to compile %v: %v", source, strings.TrimRight(log, "\x00"))
 	}
 	return shader, nil
 }
 
 // initGlfw initializes glfw and returns a Window to use
-func initGlfw() *glfw.Window {
+func initGlfw(width, height int) *glfw.Window {
 	if err := glfw.Init(); err != nil {
 		panic(err)
 	}
+	if *cellSize > 0 {
+		width, height = cellWindowSize(*rows, *columns, *cellSize)
+	}
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.WindowHint(glfw.Resizable, glfw.True)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(width, height, "Conway's Game of Life", nil, nil)
+	if *hidden {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+	}
+	glfw.WindowHint(glfw.Samples, *msaa)
+
+	window, err := glfw.CreateWindow(width, height, windowTitle, nil, nil)
+	if err != nil && *msaa > 0 {
+		// Some drivers can't make a multisampled window at all, which is
+		// no reason not to run.
+		logWarnf("Failed to create a window with %dx MSAA, trying without: %v", *msaa, err)
+		glfw.WindowHint(glfw.Samples, 0)
+		window, err = glfw.CreateWindow(width, height, windowTitle, nil, nil)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -292,34 +1213,81 @@ func initGlfw() *glfw.Window {
 	return window
 }
 
-// initOpenGL initializes OpenGL and returns an initialized program
-func initOpenGL() uint32 {
+// initOpenGL initializes OpenGL and returns an initialized program along with
+// the location of its squareColor uniform
+func initOpenGL() (uint32, int32, error) {
 	if err := gl.Init(); err != nil {
-		panic(err)
+		return 0, 0, fmt.Errorf("failed to initialize OpenGL: %v", err)
 	}
 	version := gl.GoStr(gl.GetString(gl.VERSION))
-	log.Println("OpenGL version", version)
+	logInfof("OpenGL version %s", version)
+
+	if *msaa > 0 {
+		// The driver may have given the window fewer samples than asked
+		// for, or none at all.
+		var samples int32
+		gl.GetIntegerv(gl.SAMPLES, &samples)
+		if int(samples) < *msaa {
+			logWarnf("Asked for %dx MSAA but got %dx", *msaa, samples)
+		}
+		gl.Enable(gl.MULTISAMPLE)
+	}
+
+	gl.ClearColor(backgroundColor[0], backgroundColor[1], backgroundColor[2], backgroundColor[3])
 
-	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
+	prog, err := makeProgram(vertexShaderSource, fragmentShaderSource)
 	if err != nil {
-		panic(err)
+		return 0, 0, err
 	}
-	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	return prog, gl.GetUniformLocation(prog, gl.Str("squareColor\x00")), nil
+}
+
+// makeProgram compiles the given shaders and links them into a program
+func makeProgram(vertexSource, fragmentSource string) (uint32, error) {
+	vertexShader, err := compileShader(vertexSource, gl.VERTEX_SHADER)
 	if err != nil {
-		panic(err)
+		return 0, err
+	}
+	defer gl.DeleteShader(vertexShader)
+
+	fragmentShader, err := compileShader(fragmentSource, gl.FRAGMENT_SHADER)
+	if err != nil {
+		return 0, err
 	}
+	defer gl.DeleteShader(fragmentShader)
 
 	prog := gl.CreateProgram()
 	gl.AttachShader(prog, vertexShader)
 	gl.AttachShader(prog, fragmentShader)
 	gl.LinkProgram(prog)
-	return prog
+	if err := checkProgramLink(prog); err != nil {
+		gl.DeleteProgram(prog)
+		return 0, err
+	}
+	return prog, nil
 }
 
-// makeVao initializes and returns a vertex array from the points provided
-func makeVao(points []float32) uint32 {
-	var vbo uint32
+// checkProgramLink returns an error with the driver's info log if the program
+// failed to link.
+func checkProgramLink(prog uint32) error {
+	var status int32
+	gl.GetProgramiv(prog, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(prog, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(prog, logLength, nil, gl.Str(log))
+
+		return fmt.Errorf("failed to link program: %v", strings.TrimRight(log, "\x00"))
+	}
+	return nil
+}
 
+// makeVao initializes and returns a vertex array from the points provided,
+// along with the buffer holding them. Both have to be deleted once they're no
+// longer needed.
+func makeVao(points []float32) (vao, vbo uint32) {
 	gl.GenBuffers(1, &vbo)
 	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, 4*len(points), gl.Ptr(points), gl.STATIC_DRAW)
@@ -329,7 +1297,6 @@ func makeVao(points []float32) uint32 {
 	//gl.BindBuffer(gl.ARRAY_BUFFER, vboColor)
 	//gl.BufferData(gl.ARRAY_BUFFER, 4*len(color), gl.Ptr(color), gl.STATIC_DRAW)
 
-	var vao uint32
 	gl.GenVertexArrays(1, &vao)
 	gl.BindVertexArray(vao)
 	gl.EnableVertexAttribArray(0)
@@ -338,5 +1305,5 @@ func makeVao(points []float32) uint32 {
 	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil)
 	//gl.VertexAttribPointer(1, 4, gl.FLOAT, false, 0, nil)
 
-	return vao
+	return vao, vbo
 }
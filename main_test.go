@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// bounds returns the smallest and largest x and y of a list of x, y, z
+// vertices.
+func bounds(points []float32) (minX, minY, maxX, maxY float32) {
+	minX, minY = float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY = float32(math.Inf(-1)), float32(math.Inf(-1))
+	for i := 0; i < len(points); i += 3 {
+		minX = float32(math.Min(float64(minX), float64(points[i])))
+		maxX = float32(math.Max(float64(maxX), float64(points[i])))
+		minY = float32(math.Min(float64(minY), float64(points[i+1])))
+		maxY = float32(math.Max(float64(maxY), float64(points[i+1])))
+	}
+	return minX, minY, maxX, maxY
+}
+
+func TestCellPointsCorners(t *testing.T) {
+	const epsilon = 1e-6
+
+	for _, size := range [][2]int{{1, 1}, {50, 50}, {20, 7}, {3, 100}} {
+		rows, columns := size[0], size[1]
+		width, height := 2/float32(rows), 2/float32(columns)
+
+		// The corner cells fill the corners of normalized device
+		// coordinates, one cell's size in from them.
+		corners := []struct {
+			x, y       int
+			minX, minY float32
+		}{
+			{0, 0, -1, -1},
+			{rows - 1, 0, 1 - width, -1},
+			{0, columns - 1, -1, 1 - height},
+			{rows - 1, columns - 1, 1 - width, 1 - height},
+		}
+		for _, c := range corners {
+			minX, minY, maxX, maxY := bounds(cellPoints(c.x, c.y, rows, columns))
+			got := [4]float32{minX, minY, maxX, maxY}
+			want := [4]float32{c.minX, c.minY, c.minX + width, c.minY + height}
+			for i := range got {
+				if math.Abs(float64(got[i]-want[i])) > epsilon {
+					t.Errorf("%dx%d grid: cell %d,%d spans %v, want %v", rows, columns, c.x, c.y, got, want)
+					break
+				}
+			}
+		}
+	}
+}
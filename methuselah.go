@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// settleWindow is how many generations the population has to keep
+	// repeating before a methuselah counts as settled.
+	settleWindow = 100
+
+	// settleMaxPeriod is the longest period over which the population can
+	// repeat and count as settled, long enough for the common oscillators
+	// left behind.
+	settleMaxPeriod = 6
+)
+
+var (
+	methuselahName = flag.String("methuselah", "", "run one of the methuselahs "+strings.Join(methuselahNames(), ", ")+" without a window until it settles, and report when it did and what was left (use -grow for the unbounded field the known results are for)")
+)
+
+// methuselah is a small pattern that takes a long time to settle, with the
+// generation it settles at and the population it's left with on an unbounded
+// field.
+type methuselah struct {
+	rle         string
+	generations int
+	population  int
+}
+
+// methuselahs are the patterns -methuselah runs.
+var methuselahs = map[string]methuselah{
+	"rpentomino": {"x = 3, y = 3\nb2o$2o$bo!", 1103, 116},
+	"acorn":      {"x = 7, y = 3\nbo5b$3bo3b$2o2b3o!", 5206, 633},
+	"diehard":    {"x = 8, y = 3\n6bob$2o6b$bo3b3o!", 130, 0},
+}
+
+// methuselahNames returns the names of the methuselahs, sorted.
+func methuselahNames() []string {
+	var names []string
+	for name := range methuselahs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// settle steps the game until its population has repeated with a period of
+// at most settleMaxPeriod for settleWindow generations, and returns the
+// generation it started repeating at and the population then. The gliders a
+// methuselah sends off never settle in place, but they keep the population
+// steady, so settling is judged by the population alone. ok is false if ctx
+// was done first.
+func settle(ctx context.Context, g *Game) (generation, live int, ok bool) {
+	pops := []int{population(g.board)}
+	for ctx.Err() == nil {
+		// A board that has stopped changing isn't stepped any more,
+		// and its population stays as it is.
+		g.Step()
+		pops = append(pops, population(g.board))
+
+		n := len(pops) - 1
+		for p := 1; p <= settleMaxPeriod && n >= settleWindow+p; p++ {
+			if !repeats(pops, p, n-settleWindow+1) {
+				continue
+			}
+
+			// It has settled, from just after the last generation
+			// that broke the pattern.
+			start := n - settleWindow + 1
+			for start > p && pops[start-1] == pops[start-1-p] {
+				start--
+			}
+			generation = start - p
+			if generation < 0 {
+				generation = 0
+			}
+			return generation, pops[generation], true
+		}
+	}
+	return 0, 0, false
+}
+
+// repeats reports whether every population in pops from index from on
+// matches the one p generations before it.
+func repeats(pops []int, p, from int) bool {
+	for t := from; t < len(pops); t++ {
+		if pops[t] != pops[t-p] {
+			return false
+		}
+	}
+	return true
+}
+
+// runMethuselah runs the game, started from the named methuselah, until it
+// settles, and writes when that was and what was left to out, along with
+// what happens on an unbounded field if the board isn't one.
+func runMethuselah(ctx context.Context, g *Game, name string, out io.Writer) {
+	generation, live, ok := settle(ctx, g)
+	if !ok {
+		logInfof("Interrupted")
+		return
+	}
+
+	m := methuselahs[name]
+	fmt.Fprintf(out, "%s settled at generation %d with %d cells\n", name, generation, live)
+	switch {
+	case *grow:
+		fmt.Fprintf(out, "On an unbounded field it settles at generation %d with %d cells", m.generations, m.population)
+		if g.growth.capped {
+			fmt.Fprintf(out, ", but this board hit -grow-max and stopped being unbounded")
+		}
+		fmt.Fprintln(out)
+	case g.board.Wrap:
+		fmt.Fprintf(out, "This was on a %dx%d torus, where what it sends off comes back around; on an unbounded field (-grow) it settles at generation %d with %d cells\n", g.board.Rows(), g.board.Columns(), m.generations, m.population)
+	default:
+		fmt.Fprintf(out, "This was on a %dx%d bounded board, where what it sends off is stopped at the edges; on an unbounded field (-grow) it settles at generation %d with %d cells\n", g.board.Rows(), g.board.Columns(), m.generations, m.population)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// methuselahGame returns a game started from the named methuselah in the
+// middle of a board of size by size, growing it if growing is set, and
+// otherwise wrapping it.
+func methuselahGame(name string, size int, growing bool) *Game {
+	board := newTestBoard(size, size, mustParseRLE(methuselahs[name].rle), size/2, size/2)
+	if !growing {
+		return newGame(board, nil, nil, nil, nil)
+	}
+	board.Wrap = false
+	return newGame(board, nil, newGrower(1), nil, nil)
+}
+
+func TestSettle(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   [][]bool
+		wantGen   int
+		wantAlive int
+	}{
+		// Period 2 with the same population throughout.
+		{"blinker", mustParseRLE("x = 3, y = 1\n3o!"), 0, 3},
+
+		// Period 3, with the population changing along with it.
+		{"pulsar", brushes["pulsar"], 0, 48},
+
+		// Fills in to a block at generation 1.
+		{"pre-block", mustParseRLE("x = 2, y = 2\no$2o!"), 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(newTestBoard(40, 40, tt.pattern, 12, 12), nil, nil, nil, nil)
+			generation, live, ok := settle(context.Background(), g)
+			if !ok || generation != tt.wantGen || live != tt.wantAlive {
+				t.Errorf("settle = %d, %d, %t, want generation %d with %d cells", generation, live, ok, tt.wantGen, tt.wantAlive)
+			}
+		})
+	}
+}
+
+func TestSettleMethuselahs(t *testing.T) {
+	names := []string{"diehard", "rpentomino"}
+	if testing.Short() {
+		names = names[:1]
+	}
+
+	// On a growing board, standing in for an unbounded field, the known
+	// results hold.
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			m := methuselahs[name]
+			generation, live, ok := settle(context.Background(), methuselahGame(name, 40, true))
+			if !ok || generation != m.generations || live != m.population {
+				t.Errorf("settled at generation %d with %d cells, want %d with %d", generation, live, m.generations, m.population)
+			}
+		})
+	}
+}
+
+func TestRunMethuselahTorus(t *testing.T) {
+	defer func(g bool) { *grow = g }(*grow)
+	*grow = false
+
+	// On a small torus the R-pentomino's gliders come back around and
+	// settle it far sooner than on an unbounded field, which the report
+	// points out.
+	var out strings.Builder
+	g := methuselahGame("rpentomino", 50, false)
+	runMethuselah(context.Background(), g, "rpentomino", &out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "rpentomino settled at generation ") {
+		t.Fatalf("report = %q", out.String())
+	}
+	if strings.Contains(lines[0], "generation 1103 ") || !strings.Contains(lines[1], "50x50 torus") || !strings.Contains(lines[1], "generation 1103 with 116 cells") {
+		t.Errorf("report = %q, want a torus result and a note of the unbounded one", out.String())
+	}
+}
+
+func TestSettleInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, ok := settle(ctx, methuselahGame("rpentomino", 40, true)); ok {
+		t.Error("settle reported settling after ctx was done")
+	}
+}
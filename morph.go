@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	ruleFrom  = flag.String("rulefrom", "", "with -ruleto, the rule to morph from, in place of -rule")
+	ruleTo    = flag.String("ruleto", "", "a rule to morph the board over to from -rule during -morphgens generations: each generation every cell goes by one rule or the other, by a draw seeded from its position that favors -ruleto more and more")
+	morphGens = flag.Int("morphgens", 0, "how many generations -ruleto takes to replace -rule entirely")
+)
+
+// morphRule is the rule of -ruleto, which the boards morph over to.
+var morphRule life.Rule
+
+// parseMorphRules sets morphRule from -ruleto, and returns the rule the boards
+// start out under: -rulefrom if it's given, or else r, the rule of -rule.
+func parseMorphRules(r life.Rule) (life.Rule, error) {
+	if *ruleTo == "" {
+		return r, nil
+	}
+
+	var err error
+	if morphRule, err = life.ParseRule(*ruleTo); err != nil {
+		return r, fmt.Errorf("invalid -ruleto: %v", err)
+	}
+	if *ruleFrom == "" {
+		return r, nil
+	}
+	from, err := life.ParseRule(*ruleFrom)
+	if err != nil {
+		return r, fmt.Errorf("invalid -rulefrom: %v", err)
+	}
+	return from, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+func TestMakeBoardMorphs(t *testing.T) {
+	defer func(from, to string, gens int, r life.Rule) {
+		*ruleFrom, *ruleTo, *morphGens, morphRule = from, to, gens, r
+	}(*ruleFrom, *ruleTo, *morphGens, morphRule)
+
+	highLife, _ := life.ParseRule("B36/S23")
+	seeds, _ := life.ParseRule("B2/S")
+	*ruleFrom, *ruleTo, *morphGens = "B2/S", "B36/S23", 50
+
+	from, err := parseMorphRules(conway)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != seeds || morphRule != highLife {
+		t.Errorf("morphing from %v to %v, want from -rulefrom %v to -ruleto %v", from, morphRule, seeds, highLife)
+	}
+
+	b := makeBoard(10, 10, 0.3, 208, nil, from)
+	if b.Rule != seeds || b.MorphRule != highLife || b.MorphGenerations != 50 || b.MorphSeed != 208 {
+		t.Errorf("board morphs from %v to %v over %d generations with seed %d, want %v to %v over 50 with 208",
+			b.Rule, b.MorphRule, b.MorphGenerations, b.MorphSeed, seeds, highLife)
+	}
+
+	*ruleTo = "B9/S"
+	if _, err := parseMorphRules(conway); err == nil {
+		t.Error("parseMorphRules accepted -ruleto B9/S")
+	}
+}
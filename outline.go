@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var (
+	outline      = flag.Bool("outline", false, "draw cells as outlines rather than filled shapes")
+	outlineWidth = flag.Float64("outline-width", 1, "width in pixels of the lines drawn with -outline, as far as the graphics driver allows")
+)
+
+// cellOutline returns the corners of the cell at x, y on a rows by columns
+// grid, in order around it, to be drawn as a line loop.
+func cellOutline(x, y, rows, columns int) []float32 {
+	var corners []float32
+	if *hexGrid {
+		for _, corner := range hexCorners(x, y, rows, columns) {
+			corners = append(corners, corner[0], corner[1], 0)
+		}
+		return corners
+	}
+
+	// Walk the corners of the square in order: top-left, bottom-left,
+	// bottom-right and top-right.
+	points := cellPoints(x, y, rows, columns)
+	for _, v := range []int{0, 1, 2, 4} {
+		corners = append(corners, points[v*3:v*3+3]...)
+	}
+	return corners
+}
+
+// outlineLineWidth returns the line width to draw -outline with: -outline-width
+// if the driver can draw lines that wide, or the nearest width it can. Core
+// profiles often only draw lines one pixel wide. OpenGL has to be initialized.
+func outlineLineWidth() float32 {
+	width := float32(*outlineWidth)
+
+	var limits [2]float32
+	gl.GetFloatv(gl.ALIASED_LINE_WIDTH_RANGE, &limits[0])
+	if width > limits[1] {
+		width = limits[1]
+	}
+
+	// Forward compatible contexts reject wide lines whatever range they
+	// report.
+	gl.LineWidth(width)
+	if gl.GetError() != gl.NO_ERROR {
+		width = 1
+	}
+	gl.LineWidth(1)
+
+	if width != float32(*outlineWidth) {
+		logWarnf("Asked for %g pixel outlines but can only draw %g", *outlineWidth, width)
+	}
+	return width
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"time"
+)
+
+// maxLag is how many frames the loop may fall behind before the pacer gives up
+// catching up and starts again from the present. Without it a long stall, such
+// as the window being dragged, would be followed by a burst of frames run flat
+// out.
+const maxLag = 5
+
+// pacer schedules frames against a fixed timeline rather than sleeping for
+// whatever is left of each frame. A frame that runs long is made up for by
+// shorter sleeps afterwards, so the average rate stays on target.
+type pacer struct {
+	// next is when the next frame is due. It's zero before the first frame.
+	next time.Time
+}
+
+// delay returns how long to sleep at now, the end of a frame, so the next
+// frame starts on time given frames interval apart. It never returns a
+// negative duration.
+func (p *pacer) delay(now time.Time, interval time.Duration) time.Duration {
+	if p.next.IsZero() {
+		p.next = now
+	}
+	p.next = p.next.Add(interval)
+
+	if lag := now.Sub(p.next); lag > maxLag*interval {
+		p.next = now
+		return 0
+	}
+
+	if d := p.next.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPacerDelay(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	start := time.Unix(100, 0)
+
+	// Each frame ends at the given time after the start.
+	frames := []struct {
+		name  string
+		ends  time.Duration
+		sleep time.Duration
+	}{
+		{"first frame", 0, interval},
+
+		// Due at 200ms, so a slow frame sleeps for less.
+		{"slow frame", 150 * time.Millisecond, 50 * time.Millisecond},
+
+		// Due at 300ms, so it runs straight on.
+		{"late frame", 350 * time.Millisecond, 0},
+
+		// Due at 400ms, catching up on the time lost.
+		{"catching up", 360 * time.Millisecond, 40 * time.Millisecond},
+
+		// More than maxLag frames behind, the timeline starts over.
+		{"stall", 10 * time.Second, 0},
+		{"after the stall", 10*time.Second + 10*time.Millisecond, 90 * time.Millisecond},
+	}
+
+	var p pacer
+	for _, f := range frames {
+		if d := p.delay(start.Add(f.ends), interval); d != f.sleep {
+			t.Errorf("%s: delay = %v, want %v", f.name, d, f.sleep)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+const (
+	// life106Header is the first line of every Life 1.06 file.
+	life106Header = "#Life 1.06"
+
+	// stdinPattern is the -pattern that reads the pattern from standard
+	// input.
+	stdinPattern = "-"
+
+	// maxPatternCells is the most cells, live or dead, a pattern file may
+	// describe. RLE and Life 1.06 can ask for an enormous grid in a few
+	// bytes, so this stops a bad file from using up all the memory.
+	maxPatternCells = 1 << 24
+
+	// The values of -oversized.
+	oversizedError = "error"
+	oversizedCrop  = "crop"
+	oversizedScale = "scale"
+)
+
+var oversized = flag.String("oversized", oversizedError, "what to do with a -pattern larger than the grid: "+oversizedError+" stops, "+oversizedCrop+" keeps the middle of it that fits, "+oversizedScale+" shrinks it to fit, each cell alive if any in its block was")
+
+// loadPattern reads a pattern file in RLE, Life 1.06 or plaintext format, or
+// reads one from standard input if path is stdinPattern.
+func loadPattern(path string) ([][]bool, error) {
+	name := path
+	var data []byte
+	var err error
+	if path == stdinPattern {
+		name = "standard input"
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := parsePattern(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return pattern, nil
+}
+
+// parsePattern decodes a pattern in whichever format it's in. Life 1.06 has a
+// header saying so. Plaintext starts with ! comments or a row of . and O
+// cells, where RLE starts with # comments or its x = .. header.
+func parsePattern(data string) ([][]bool, error) {
+	start := strings.TrimSpace(data)
+	switch {
+	case start == "":
+		return nil, errors.New("no pattern, the input is empty")
+	case strings.HasPrefix(start, life106Header):
+		return parseLife106(strings.NewReader(data))
+	case strings.IndexByte("!.O*", start[0]) >= 0:
+		return parsePlaintext(strings.NewReader(data))
+	default:
+		return parseRLE(strings.NewReader(data))
+	}
+}
+
+// parseRLE decodes a pattern in the run length encoded format used by most
+// Life software: an "x = .., y = .." header followed by runs of b (dead) and
+// o (alive) cells, with $ ending each line and ! ending the pattern. Lines
+// starting with # are comments.
+//
+// The pattern is returned indexed [x][y] like the grid, with y counting up from
+// the bottom so it appears the right way up when drawn.
+func parseRLE(r io.Reader) ([][]bool, error) {
+	var width, height int
+	var haveHeader, done bool
+
+	type point struct{ col, row int }
+	var live []point
+	var col, row, count int
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() && !done {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !haveHeader {
+			var err error
+			if width, height, err = parseRLEHeader(line); err != nil {
+				return nil, err
+			}
+			haveHeader = true
+			continue
+		}
+
+		for _, ch := range line {
+			switch {
+			case ch >= '0' && ch <= '9':
+				if count = count*10 + int(ch-'0'); count > maxPatternCells {
+					return nil, fmt.Errorf("run of %d cells is too long", count)
+				}
+				continue
+			case ch == ' ' || ch == '\t':
+				continue
+			}
+
+			run := count
+			if run == 0 {
+				run = 1
+			}
+			count = 0
+
+			switch ch {
+			case 'b':
+				col += run
+			case 'o':
+				for i := 0; i < run; i++ {
+					live = append(live, point{col, row})
+					col++
+				}
+			case '$':
+				row += run
+				col = 0
+			case '!':
+				done = true
+			default:
+				return nil, fmt.Errorf("unexpected %q in pattern", ch)
+			}
+
+			if col > maxPatternCells || row > maxPatternCells {
+				return nil, fmt.Errorf("pattern is more than %d cells across", maxPatternCells)
+			}
+			if done {
+				break
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !haveHeader {
+		return nil, errors.New("missing \"x = .., y = ..\" header")
+	}
+
+	// Trust the cells over the header if the two disagree.
+	for _, p := range live {
+		if p.col >= width {
+			width = p.col + 1
+		}
+		if p.row >= height {
+			height = p.row + 1
+		}
+	}
+	if width == 0 || height == 0 {
+		return nil, errors.New("empty pattern")
+	}
+	if err := checkPatternSize(width, height); err != nil {
+		return nil, err
+	}
+
+	pattern := make([][]bool, width)
+	for x := range pattern {
+		pattern[x] = make([]bool, height)
+	}
+	for _, p := range live {
+		pattern[p.col][height-1-p.row] = true
+	}
+
+	return pattern, nil
+}
+
+// parseLife106 decodes a pattern in the Life 1.06 format: a "#Life 1.06"
+// header followed by one live cell per line, as an x y coordinate pair. The
+// coordinates may be negative and have y counting down, as in RLE.
+//
+// The pattern is returned indexed [x][y] from the corner of its bounding box,
+// with y counting up from the bottom, the same as parseRLE.
+func parseLife106(r io.Reader) ([][]bool, error) {
+	type point struct{ x, y int }
+	var live []point
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != life106Header {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("missing %q header", life106Header)
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid cell %q: want x y", line)
+		}
+		x, errX := strconv.Atoi(fields[0])
+		y, errY := strconv.Atoi(fields[1])
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid cell %q: want x y", line)
+		}
+		if x < -maxPatternCells || x > maxPatternCells || y < -maxPatternCells || y > maxPatternCells {
+			return nil, fmt.Errorf("cell %q is too far out", line)
+		}
+		live = append(live, point{x, y})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(live) == 0 {
+		return nil, errors.New("empty pattern")
+	}
+
+	minX, minY, maxX, maxY := live[0].x, live[0].y, live[0].x, live[0].y
+	for _, p := range live {
+		if p.x < minX {
+			minX = p.x
+		}
+		if p.x > maxX {
+			maxX = p.x
+		}
+		if p.y < minY {
+			minY = p.y
+		}
+		if p.y > maxY {
+			maxY = p.y
+		}
+	}
+
+	if err := checkPatternSize(maxX-minX+1, maxY-minY+1); err != nil {
+		return nil, err
+	}
+	pattern := make([][]bool, maxX-minX+1)
+	for x := range pattern {
+		pattern[x] = make([]bool, maxY-minY+1)
+	}
+	for _, p := range live {
+		pattern[p.x-minX][maxY-p.y] = true
+	}
+
+	return pattern, nil
+}
+
+// parsePlaintext decodes a pattern in the plaintext .cells format written by
+// saveBoard: one line per row, top row first, with O (or *) for live cells
+// and . for dead ones. Lines starting with ! are comments, and short lines are
+// padded with dead cells.
+//
+// The pattern is returned indexed [x][y] with y counting up from the bottom,
+// the same as parseRLE.
+func parsePlaintext(r io.Reader) ([][]bool, error) {
+	var lines []string
+	var width int
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		for _, ch := range line {
+			if ch != '.' && ch != 'O' && ch != '*' {
+				return nil, fmt.Errorf("unexpected %q in pattern", ch)
+			}
+		}
+		lines = append(lines, line)
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Blank lines are rows of dead cells, except at the end.
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if width == 0 || len(lines) == 0 {
+		return nil, errors.New("empty pattern")
+	}
+
+	height := len(lines)
+	pattern := make([][]bool, width)
+	for x := range pattern {
+		pattern[x] = make([]bool, height)
+	}
+	for row, line := range lines {
+		for col, ch := range line {
+			pattern[col][height-1-row] = ch != '.'
+		}
+	}
+
+	return pattern, nil
+}
+
+// parseRLEHeader reads the pattern's width and height from an RLE header line
+// such as "x = 3, y = 3, rule = B3/S23".
+func parseRLEHeader(line string) (width, height int, err error) {
+	var haveX, haveY bool
+	for _, field := range strings.Split(line, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return 0, 0, fmt.Errorf("invalid header %q", line)
+		}
+
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "x":
+			width, err = strconv.Atoi(value)
+			haveX = true
+		case "y":
+			height, err = strconv.Atoi(value)
+			haveY = true
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid header %q: %v", line, err)
+		}
+	}
+
+	if !haveX || !haveY || width < 0 || height < 0 {
+		return 0, 0, fmt.Errorf("invalid header %q", line)
+	}
+	return width, height, nil
+}
+
+// checkPatternSize returns an error if a width by height pattern would have
+// more than maxPatternCells cells.
+func checkPatternSize(width, height int) error {
+	if width > maxPatternCells/height {
+		return fmt.Errorf("%dx%d pattern is too large, the most is %d cells", width, height, maxPatternCells)
+	}
+	return nil
+}
+
+// fitPattern fits a pattern onto a rows by columns grid as fit, one of the
+// values of -oversized, says. A pattern that already fits is returned as it is.
+//
+// Cropping keeps the part that centering would leave on the grid: where an odd
+// number of cells has to go, the extra one comes off the right, or the top.
+// Scaling shrinks both axes by the same whole factor, the smallest that fits,
+// so the pattern keeps its shape, and a cell of the result is alive if any of
+// the factor by factor block of cells it stands for is, so nothing small
+// vanishes altogether. A partial block at the right or top is reduced from
+// the cells it has.
+func fitPattern(pattern [][]bool, rows, columns int, fit string) ([][]bool, error) {
+	width, height := len(pattern), len(pattern[0])
+	if width <= rows && height <= columns {
+		return pattern, nil
+	}
+
+	switch fit {
+	case oversizedCrop:
+		x0, y0 := 0, 0
+		if width > rows {
+			x0, width = (width-rows)/2, rows
+		}
+		if height > columns {
+			y0, height = (height-columns)/2, columns
+		}
+		cropped := make([][]bool, width)
+		for x := range cropped {
+			cropped[x] = pattern[x0+x][y0 : y0+height]
+		}
+		return cropped, nil
+	case oversizedScale:
+		factor := (width + rows - 1) / rows
+		if f := (height + columns - 1) / columns; f > factor {
+			factor = f
+		}
+		scaled := make([][]bool, (width+factor-1)/factor)
+		for x := range scaled {
+			scaled[x] = make([]bool, (height+factor-1)/factor)
+		}
+		for x := range pattern {
+			for y, alive := range pattern[x] {
+				if alive {
+					scaled[x/factor][y/factor] = true
+				}
+			}
+		}
+		return scaled, nil
+	default:
+		return nil, fmt.Errorf("the %dx%d pattern doesn't fit the %dx%d grid, see -oversized", width, height, rows, columns)
+	}
+}
+
+// stampPattern clears the board and places the pattern in its center. Any
+// live cells that land outside the grid are dropped, and the number dropped
+// is returned.
+func stampPattern(board *life.Board, pattern [][]bool) int {
+	for x := range board.Cells {
+		for _, c := range board.Cells[x] {
+			c.Set(false)
+		}
+	}
+
+	offsetX := (board.Rows() - len(pattern)) / 2
+	offsetY := (board.Columns() - len(pattern[0])) / 2
+	return board.Place(pattern, offsetX, offsetY, false)
+}
+
+// saveBoard writes the board to path in the plaintext .cells format: one line
+// per row, top row first, with O for live cells and . for dead ones.
+func saveBoard(cells [][]*life.Cell, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for y := len(cells[0]) - 1; y >= 0; y-- {
+		for x := range cells {
+			if cells[x][y].Alive() {
+				w.WriteByte('O')
+			} else {
+				w.WriteByte('.')
+			}
+		}
+		w.WriteByte('\n')
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
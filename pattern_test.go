@@ -0,0 +1,221 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var conway, _ = life.ParseRule("B3/S23")
+
+// gliderCells are the live cells of a glider heading down and to the right,
+// as parsed from any of the formats, indexed [x][y] with y counting up.
+var gliderCells = [][2]int{{1, 2}, {2, 1}, {0, 0}, {1, 0}, {2, 0}}
+
+// checkPattern fails t unless the pattern is width by height with exactly the
+// live cells of want.
+func checkPattern(t *testing.T, pattern [][]bool, width, height int, want [][2]int) {
+	t.Helper()
+	if len(pattern) != width || len(pattern[0]) != height {
+		t.Fatalf("pattern is %dx%d, want %dx%d", len(pattern), len(pattern[0]), width, height)
+	}
+
+	live := map[[2]int]bool{}
+	for _, p := range want {
+		live[p] = true
+	}
+	for x := range pattern {
+		for y, alive := range pattern[x] {
+			if alive != live[[2]int{x, y}] {
+				t.Errorf("cell %d,%d alive = %v, want %v", x, y, alive, !alive)
+			}
+		}
+	}
+}
+
+func TestParseRLE(t *testing.T) {
+	tests := []struct {
+		name          string
+		rle           string
+		width, height int
+		want          [][2]int
+	}{
+		{"glider", "#C A glider\nx = 3, y = 3, rule = B3/S23\nbob$2bo$3o!\n", 3, 3, gliderCells},
+		{"split across lines", "x = 3, y = 3\nbo\nb$2bo$3\no!\n", 3, 3, gliderCells},
+
+		// Trailing dead cells and rows are left out, so the header gives
+		// the size.
+		{"trailing dead cells", "x = 4, y = 2\no!\n", 4, 2, [][2]int{{0, 1}}},
+		{"blank rows", "x = 1, y = 3\no2$o!\n", 1, 3, [][2]int{{0, 2}, {0, 0}}},
+
+		// But the cells win when the header is too small.
+		{"header too small", "x = 1, y = 1\n3o!\n", 3, 1, [][2]int{{0, 0}, {1, 0}, {2, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := parseRLE(strings.NewReader(tt.rle))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPattern(t, pattern, tt.width, tt.height, tt.want)
+		})
+	}
+}
+
+func TestParseRLEInvalid(t *testing.T) {
+	for _, rle := range []string{
+		"",
+		"bo$2bo$3o!\n",
+		"x = 3\nbo$2bo$3o!\n",
+		"x = 2, y = 2\n2o$2q!\n",
+		"x = 0, y = 0\n!\n",
+		// Headers and runs too large to allocate.
+		"x = 2000000000, y = 2000000000\no!\n",
+		"x = 99999999999999999999, y = 1\no!\n",
+		"x = 1, y = 1\n99999999999999999999bo!\n",
+		"x = 1, y = 1\n16777216b16777216bo!\n",
+		"x = 5000, y = 5000\no!\n",
+	} {
+		if _, err := parseRLE(strings.NewReader(rle)); err == nil {
+			t.Errorf("parseRLE(%q) succeeded, want an error", rle)
+		}
+	}
+}
+
+func TestStampPattern(t *testing.T) {
+	pattern, err := parseRLE(strings.NewReader("x = 3, y = 3\nbob$2bo$3o!\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := life.NewBoard(5, 5, conway)
+	b.Cells[0][0].Set(true)
+	if clipped := stampPattern(b, pattern); clipped != 0 {
+		t.Errorf("stamping on a 5x5 board clipped %d cells, want 0", clipped)
+	}
+	if b.Population() != 5 || b.Alive(0, 0) || !b.Alive(2, 3) {
+		t.Error("pattern wasn't stamped alone in the middle of the board")
+	}
+
+	if clipped := stampPattern(life.NewBoard(2, 2, conway), pattern); clipped == 0 {
+		t.Error("stamping on a 2x2 board clipped nothing")
+	}
+}
+
+func TestParseLife106(t *testing.T) {
+	tests := []struct {
+		name          string
+		lif           string
+		width, height int
+		want          [][2]int
+	}{
+		{"glider", "#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n", 3, 3, gliderCells},
+		{"comments and blank lines", "#Life 1.06\n#D A glider\n\n10 9\n11 10\n9 11\n10 11\n11 11\n", 3, 3, gliderCells},
+		{"one cell", "#Life 1.06\n-5 -5\n", 1, 1, [][2]int{{0, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := parseLife106(strings.NewReader(tt.lif))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPattern(t, pattern, tt.width, tt.height, tt.want)
+		})
+	}
+}
+
+func TestParseLife106Invalid(t *testing.T) {
+	for _, lif := range []string{
+		"#Life 1.06\n",
+		"#Life 1.05\n0 0\n",
+		"#Life 1.06\n0 x\n",
+		"#Life 1.06\n0 0 0\n",
+		"#Life 1.06\n0 0\n5000 5000\n",
+		"#Life 1.06\n-9223372036854775808 0\n9223372036854775807 0\n",
+	} {
+		if _, err := parseLife106(strings.NewReader(lif)); err == nil {
+			t.Errorf("parseLife106(%q) succeeded, want an error", lif)
+		}
+	}
+}
+
+func TestFitPattern(t *testing.T) {
+	// A 4x4 diagonal from the top left down to the bottom right.
+	square := "O...\n.O..\n..O.\n...O\n"
+
+	tests := []struct {
+		name          string
+		pattern       string
+		rows, columns int
+		fit           string
+		width, height int
+		want          [][2]int
+	}{
+		{"fits", square, 4, 4, oversizedError, 4, 4, [][2]int{{0, 3}, {1, 2}, {2, 1}, {3, 0}}},
+
+		// The extra column comes off the right, and of the two extra
+		// rows one comes off each side.
+		{"crop", square, 3, 2, oversizedCrop, 3, 2, [][2]int{{1, 1}, {2, 0}}},
+		{"crop across", square, 2, 8, oversizedCrop, 2, 4, [][2]int{{0, 2}, {1, 1}}},
+
+		{"scale", square, 2, 2, oversizedScale, 2, 2, [][2]int{{0, 1}, {1, 0}}},
+		{"scale dead block", "O...\n....\n....\n...O\n", 2, 2, oversizedScale, 2, 2, [][2]int{{0, 1}, {1, 0}}},
+
+		// The factor is 3, leaving a partial block at the right, and
+		// both axes shrink by it to keep the shape.
+		{"scale partial block", "....O\n", 2, 1, oversizedScale, 2, 1, [][2]int{{1, 0}}},
+		{"scale keeps shape", "OO..OO\n", 3, 3, oversizedScale, 3, 1, [][2]int{{0, 0}, {2, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := parsePlaintext(strings.NewReader(tt.pattern))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fitted, err := fitPattern(pattern, tt.rows, tt.columns, tt.fit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPattern(t, fitted, tt.width, tt.height, tt.want)
+		})
+	}
+}
+
+func TestFitPatternError(t *testing.T) {
+	pattern := make([][]bool, 5)
+	for x := range pattern {
+		pattern[x] = make([]bool, 2)
+	}
+	if _, err := fitPattern(pattern, 4, 4, oversizedError); err == nil {
+		t.Error("a 5x2 pattern fit a 4x4 grid without an error")
+	}
+}
+
+func TestFitPatternCropCenters(t *testing.T) {
+	// Stamping the cropped pattern leaves the same board as stamping the
+	// whole pattern and letting centering clip it.
+	pattern, err := parsePlaintext(strings.NewReader("O.O.O\n.OOO.\nO...O\n.O.O.\nOOOOO\nO.O.O\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range [][2]int{{3, 3}, {4, 2}, {2, 5}, {5, 1}} {
+		rows, columns := size[0], size[1]
+		cropped, err := fitPattern(pattern, rows, columns, oversizedCrop)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		whole, crop := life.NewBoard(rows, columns, conway), life.NewBoard(rows, columns, conway)
+		stampPattern(whole, pattern)
+		if clipped := stampPattern(crop, cropped); clipped != 0 {
+			t.Errorf("%dx%d grid: the cropped pattern still lost %d cells", rows, columns, clipped)
+		}
+		if !boardsEqual(snapshot(whole.Cells), crop.Cells) {
+			t.Errorf("%dx%d grid: cropping isn't where centering puts the pattern", rows, columns)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"hash/fnv"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	detectPeriod = flag.Int("detect-period", 0, "report when the board repeats within this many generations (0 turns it off)")
+)
+
+// periodDetector notices when the board starts repeating itself, by comparing
+// a hash of each generation with the hashes of the ones before it.
+type periodDetector struct {
+	// hashes is a ring buffer of recent board hashes; next is where the
+	// next one goes and filled how many slots hold one.
+	hashes []uint64
+	next   int
+	filled int
+
+	// reported is the period last logged, so a cycle is only reported once.
+	reported int
+}
+
+func newPeriodDetector(window int) *periodDetector {
+	return &periodDetector{hashes: make([]uint64, window)}
+}
+
+// observe records the current generation and returns the shortest period,
+// up to the window, over which the board has repeated, or zero if it hasn't.
+// A newly found period is logged. A period of 1 is a still life, which is
+// reported as a stable board instead.
+func (d *periodDetector) observe(cells [][]*life.Cell) int {
+	h := hashBoard(cells)
+
+	var period int
+	for k := 1; k <= d.filled; k++ {
+		if d.hashes[(d.next-k+len(d.hashes))%len(d.hashes)] == h {
+			period = k
+			break
+		}
+	}
+
+	d.hashes[d.next] = h
+	d.next = (d.next + 1) % len(d.hashes)
+	if d.filled < len(d.hashes) {
+		d.filled++
+	}
+
+	if period > 1 && period != d.reported {
+		logResultf("period-%d oscillator detected", period)
+	}
+	d.reported = period
+	return period
+}
+
+// reset forgets every generation seen so far, for a new board.
+func (d *periodDetector) reset() {
+	d.next = 0
+	d.filled = 0
+	d.reported = 0
+}
+
+// hashBoard returns an FNV-1a hash of which cells are alive.
+func hashBoard(cells [][]*life.Cell) uint64 {
+	h := fnv.New64a()
+
+	var b byte
+	var bits uint
+	for x := range cells {
+		for _, c := range cells[x] {
+			b <<= 1
+			if c.Alive() {
+				b |= 1
+			}
+			if bits++; bits == 8 {
+				h.Write([]byte{b})
+				b, bits = 0, 0
+			}
+		}
+	}
+	if bits > 0 {
+		h.Write([]byte{b})
+	}
+
+	return h.Sum64()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+// newTestBoard returns a rows by columns Conway board with pattern placed
+// with its bottom-left corner at x, y.
+func newTestBoard(rows, columns int, pattern [][]bool, x, y int) *life.Board {
+	b := life.NewBoard(rows, columns, conway)
+	b.Place(pattern, x, y, true)
+	return b
+}
+
+func TestPeriodDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern [][]bool
+		window  int
+		want    int
+	}{
+		{"block", mustParseRLE("x = 2, y = 2\n2o$2o!"), 4, 1},
+		{"blinker", mustParseRLE("x = 3, y = 1\n3o!"), 4, 2},
+		{"pulsar", brushes["pulsar"], 4, 3},
+
+		// Beyond the window the pulsar never seems to repeat.
+		{"pulsar outside the window", brushes["pulsar"], 2, 0},
+
+		// A glider's shape repeats, but the board doesn't until it's
+		// gone all the way around.
+		{"glider", brushes["glider"], 8, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(20, 20, tt.pattern, 3, 3)
+			d := newPeriodDetector(tt.window)
+			d.observe(b.Cells)
+
+			var period int
+			for i := 0; i < 12; i++ {
+				b.Step()
+				period = d.observe(b.Cells)
+			}
+			if period != tt.want {
+				t.Errorf("period = %d, want %d", period, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodDetectorReset(t *testing.T) {
+	b := newTestBoard(10, 10, mustParseRLE("x = 2, y = 2\n2o$2o!"), 3, 3)
+	d := newPeriodDetector(4)
+	d.observe(b.Cells)
+	d.reset()
+	if period := d.observe(b.Cells); period != 0 {
+		t.Errorf("period = %d straight after a reset, want 0", period)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"image"
+	"image/color/palette"
+	imagedraw "image/draw"
+	"image/gif"
+	"os"
+	"time"
+)
+
+const (
+	// maxRecordFrames caps -frames. Every frame is held in memory until the
+	// GIF is written, at a byte per pixel.
+	maxRecordFrames = 1000
+)
+
+var (
+	recordFile   = flag.String("record", "", "record the run to this animated GIF, then exit")
+	recordFrames = flag.Int("frames", 100, "generations to record with -record")
+)
+
+// recorder collects frames of the run for an animated GIF.
+type recorder struct {
+	path   string
+	frames int
+
+	// delay is how long each frame is shown, in hundredths of a second.
+	delay int
+
+	anim gif.GIF
+}
+
+func newRecorder(path string, frames int, interval time.Duration) *recorder {
+	delay := int(interval / (10 * time.Millisecond))
+	if delay < 1 {
+		delay = 1
+	}
+
+	return &recorder{
+		path:   path,
+		frames: frames,
+		delay:  delay,
+	}
+}
+
+// capture reads back the width by height framebuffer as the next frame,
+// and reports whether the recording is now complete. Like readFrame it has
+// to run on the GL thread between drawing and swapping.
+func (r *recorder) capture(width, height int) bool {
+	img := readFrame(width, height)
+
+	// Cell colors are flat, so the nearest Plan 9 color is close enough and
+	// doesn't speckle the way dithering would.
+	frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+	imagedraw.Draw(frame, frame.Bounds(), img, image.Point{}, imagedraw.Src)
+
+	r.anim.Image = append(r.anim.Image, frame)
+	r.anim.Delay = append(r.anim.Delay, r.delay)
+	return r.done()
+}
+
+func (r *recorder) done() bool {
+	return len(r.anim.Image) >= r.frames
+}
+
+// save writes the frames captured so far to the recorder's path.
+func (r *recorder) save() error {
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, &r.anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
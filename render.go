@@ -0,0 +1,256 @@
+package main
+
+import (
+	"github.com/aculler/conway-gol/life"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+const (
+	cellVertexShaderSource = `
+		#version 410
+		layout(location = 0) in vec3 vp;
+		layout(location = 1) in vec2 offset;
+		layout(location = 2) in vec4 color;
+
+		uniform mat4 view;
+
+		out vec4 cellColor;
+
+		void main() {
+			cellColor = color;
+			gl_Position = view * vec4(vp.xy + offset, vp.z, 1.0);
+		}
+` + "\x00"
+
+	cellFragmentShaderSource = `
+		#version 410
+		in vec4 cellColor;
+		out vec4 fColor;
+
+		void main() {
+			fColor = cellColor;
+		}
+` + "\x00"
+
+	// instanceSize is the number of floats describing each drawn cell: its
+	// x, y offset from the bottom-left cell followed by an RGBA color.
+	instanceSize = 6
+)
+
+// cellRenderer draws the board with a single instanced draw call. Every cell
+// shares one square, or hexagon with -hex, at the bottom-left of the grid, and
+// each instance moves it into place and gives it a color. With -outline the
+// shape is its outline, drawn as a line loop.
+type cellRenderer struct {
+	program      uint32
+	viewLocation int32
+
+	drawable    uint32
+	vbo         uint32
+	instanceVbo uint32
+
+	rows    int
+	columns int
+
+	// vertices is the number of vertices in the shared shape, and
+	// primitive how they're drawn.
+	vertices  int32
+	primitive uint32
+
+	// lineWidth is the width of the lines drawn with -outline.
+	lineWidth float32
+
+	// instances is refilled each frame with the cells to draw.
+	instances []float32
+
+	// drawn is what the instance buffer held after the last draw, and
+	// capacity how many floats it has room for, so that the next draw only
+	// uploads what changed.
+	drawn    []float32
+	capacity int
+}
+
+func newCellRenderer(rows, columns int) (*cellRenderer, error) {
+	program, err := makeProgram(cellVertexShaderSource, cellFragmentShaderSource)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &cellRenderer{
+		program:      program,
+		viewLocation: gl.GetUniformLocation(program, gl.Str("view\x00")),
+
+		rows:    rows,
+		columns: columns,
+
+		primitive: gl.TRIANGLES,
+	}
+	if *outline {
+		r.primitive = gl.LINE_LOOP
+		r.lineWidth = outlineLineWidth()
+	}
+
+	// makeVao leaves the new vertex array bound, ready for the instance
+	// attributes.
+	shape := cellShape(rows, columns)
+	r.vertices = int32(len(shape) / 3)
+	r.drawable, r.vbo = makeVao(shape)
+
+	// The offset and color advance once per instance rather than per vertex.
+	gl.GenBuffers(1, &r.instanceVbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.instanceVbo)
+
+	stride := int32(4 * instanceSize)
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, stride, nil)
+	gl.VertexAttribDivisor(1, 1)
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointer(2, 4, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.VertexAttribDivisor(2, 1)
+
+	return r, nil
+}
+
+// delete frees the renderer's GL objects.
+func (r *cellRenderer) delete() {
+	gl.DeleteVertexArrays(1, &r.drawable)
+	gl.DeleteBuffers(1, &r.vbo)
+	gl.DeleteBuffers(1, &r.instanceVbo)
+	gl.DeleteProgram(r.program)
+}
+
+// resize fits the shared shape to a rows by columns grid, for a board that
+// has changed size.
+func (r *cellRenderer) resize(rows, columns int) {
+	r.rows, r.columns = rows, columns
+
+	points := cellShape(rows, columns)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(points), gl.Ptr(points))
+}
+
+// add queues the cell at x, y to be drawn in the given color.
+func (r *cellRenderer) add(x, y int, color [4]float32) {
+	dx, dy := float32(x)*2/float32(r.rows), float32(y)*2/float32(r.columns)
+	if *hexGrid {
+		cx, cy := hexCenter(x, y, r.rows, r.columns)
+		ox, oy := hexCenter(0, 0, r.rows, r.columns)
+		dx, dy = cx-ox, cy-oy
+	}
+
+	r.instances = append(r.instances,
+		dx, dy,
+		color[0], color[1], color[2], color[3],
+	)
+}
+
+// cellShape returns the shape of the bottom-left cell on a rows by columns
+// grid.
+func cellShape(rows, columns int) []float32 {
+	if *outline {
+		return cellOutline(0, 0, rows, columns)
+	}
+	if *hexGrid {
+		return hexPoints(0, 0, rows, columns)
+	}
+	return cellPoints(0, 0, rows, columns)
+}
+
+// flush draws every queued cell with whichever program is in use, then
+// empties the queue. The whole queue is uploaded, so this suits cells that
+// are drawn in several batches a frame.
+func (r *cellRenderer) flush() {
+	if len(r.instances) == 0 {
+		return
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.instanceVbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(r.instances), gl.Ptr(r.instances), gl.STREAM_DRAW)
+	r.drawn, r.capacity = r.drawn[:0], len(r.instances)
+
+	r.drawInstances()
+	r.instances = r.instances[:0]
+}
+
+// drawInstances draws the cells in the instance buffer, as many as are
+// queued.
+func (r *cellRenderer) drawInstances() {
+	if *outline {
+		// Only the cells get wide lines, not the cursor or the graph.
+		gl.LineWidth(r.lineWidth)
+		defer gl.LineWidth(1)
+	}
+
+	gl.BindVertexArray(r.drawable)
+	gl.DrawArraysInstanced(r.primitive, 0, r.vertices, int32(len(r.instances)/instanceSize))
+}
+
+// update uploads the queued cells to the instance buffer, sending only the
+// span that differs from the last draw. A stable board sends nothing, and one
+// with a few changes sends the cells from the first change to the last.
+func (r *cellRenderer) update() {
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.instanceVbo)
+	if len(r.instances) > r.capacity {
+		// Leave room to grow, so a growing population doesn't reallocate
+		// the buffer every frame.
+		r.capacity = cap(r.instances)
+		gl.BufferData(gl.ARRAY_BUFFER, 4*r.capacity, nil, gl.DYNAMIC_DRAW)
+		r.drawn = r.drawn[:0]
+	}
+
+	start, end := changedSpan(r.drawn, r.instances)
+	if start < end {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 4*start, 4*(end-start), gl.Ptr(r.instances[start:end]))
+	}
+}
+
+// changedSpan returns the span of next, from start up to end, outside of
+// which it matches prev. It's empty when next is a prefix of prev.
+func changedSpan(prev, next []float32) (start, end int) {
+	for start < len(next) && start < len(prev) && prev[start] == next[start] {
+		start++
+	}
+
+	end = len(next)
+	if end <= len(prev) {
+		for end > start && prev[end-1] == next[end-1] {
+			end--
+		}
+	}
+	return start, end
+}
+
+// draw draws every visible cell on the board, transformed by viewMatrix. The
+// instance buffer persists between frames, and only the cells that changed
+// since the last draw are uploaded to it.
+//
+// When only live cells are ever drawn, they're found from the board's flat
+// copy rather than by visiting every cell.
+func (r *cellRenderer) draw(board *life.Board, viewMatrix mat4) {
+	gl.UseProgram(r.program)
+	gl.UniformMatrix4fv(r.viewLocation, 1, false, &viewMatrix[0])
+
+	if onlyLiveDrawn() {
+		board.EachLive(func(x, y int, c *life.Cell) {
+			color, _ := drawColor(c)
+			r.add(x, y, color)
+		})
+	} else {
+		for x := range board.Cells {
+			for y, c := range board.Cells[x] {
+				if color, ok := drawColor(c); ok {
+					r.add(x, y, color)
+				}
+			}
+		}
+	}
+	r.update()
+
+	if len(r.instances) > 0 {
+		r.drawInstances()
+	}
+
+	// Keep what was drawn to compare the next frame against, reusing the
+	// old copy's memory for the next queue.
+	r.instances, r.drawn = r.drawn[:0], r.instances
+}
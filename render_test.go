@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+func TestInstancesMatchCellPoints(t *testing.T) {
+	// Each instance moves the shared shape onto its cell, which has to land
+	// exactly where the cell's own square was drawn before instancing.
+	const epsilon = 1e-5
+
+	for _, size := range [][2]int{{1, 1}, {20, 7}, {50, 50}} {
+		rows, columns := size[0], size[1]
+		shape := cellShape(rows, columns)
+		r := &cellRenderer{rows: rows, columns: columns}
+
+		for x := 0; x < rows; x++ {
+			for y := 0; y < columns; y++ {
+				r.instances = r.instances[:0]
+				r.add(x, y, [4]float32{1, 1, 1, 1})
+				dx, dy := r.instances[0], r.instances[1]
+
+				want := cellPoints(x, y, rows, columns)
+				for i := 0; i < len(shape); i += 3 {
+					if math.Abs(float64(shape[i]+dx-want[i])) > epsilon || math.Abs(float64(shape[i+1]+dy-want[i+1])) > epsilon {
+						t.Fatalf("%dx%d grid: cell %d,%d vertex %d at %g,%g, want %g,%g",
+							rows, columns, x, y, i/3, shape[i]+dx, shape[i+1]+dy, want[i], want[i+1])
+					}
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkInstances measures the CPU side of drawing a frame: filling the
+// instance buffer from a board about a third alive and finding what changed
+// since the last frame. The GPU side is a single instanced draw call.
+func BenchmarkInstances(b *testing.B) {
+	for _, size := range []int{200, 500} {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			board := life.NewBoard(size, size, conway)
+			random := rand.New(rand.NewSource(1))
+			for x := range board.Cells {
+				for _, c := range board.Cells[x] {
+					c.Set(random.Float64() < 0.3)
+					c.Color = [4]float32{1, 1, 1, 1}
+				}
+			}
+
+			r := &cellRenderer{rows: size, columns: size}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				board.EachLive(func(x, y int, c *life.Cell) {
+					color, _ := drawColor(c)
+					r.add(x, y, color)
+				})
+				changedSpan(r.drawn, r.instances)
+				r.instances, r.drawn = r.drawn[:0], r.instances
+
+				b.StopTimer()
+				board.Step()
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func TestChangedSpanRebuildsFrames(t *testing.T) {
+	// Uploading each frame's changed span over the last frame's buffer has
+	// to leave the buffer holding the frame, whether the population grows,
+	// holds or shrinks. A glider flies toward a block, changing the
+	// instances ahead of the block's each frame while the block's stay the
+	// same, then hits it and the two die out over a few generations.
+	board := life.NewBoard(16, 16, conway)
+	for _, p := range [][2]int{{1, 13}, {2, 12}, {0, 11}, {1, 11}, {2, 11}, {9, 4}, {10, 4}, {9, 5}, {10, 5}} {
+		board.Cells[p[0]][p[1]].Set(true)
+	}
+	for x := range board.Cells {
+		for _, c := range board.Cells[x] {
+			c.Color = [4]float32{1, 1, 1, 1}
+		}
+	}
+
+	var buffer []float32
+	var shrank bool
+	r := &cellRenderer{rows: 16, columns: 16}
+	for gen := 0; gen < 40; gen++ {
+		board.EachLive(func(x, y int, c *life.Cell) {
+			color, _ := drawColor(c)
+			r.add(x, y, color)
+		})
+
+		start, end := changedSpan(r.drawn, r.instances)
+		if len(r.instances) < len(r.drawn) {
+			shrank = true
+		}
+		if len(buffer) < len(r.instances) {
+			buffer = append(buffer, make([]float32, len(r.instances)-len(buffer))...)
+		}
+		if start < end {
+			copy(buffer[start:end], r.instances[start:end])
+		}
+		for i, v := range r.instances {
+			if buffer[i] != v {
+				t.Fatalf("generation %d: instance value %d is %g after the update, want %g", gen, i, buffer[i], v)
+			}
+		}
+
+		r.instances, r.drawn = r.drawn[:0], r.instances
+		board.Step()
+	}
+	if !shrank {
+		t.Error("the population never shrank, so that case went untested")
+	}
+}
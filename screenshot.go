@@ -0,0 +1,52 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// readFrame reads back the width by height framebuffer as an image. It must
+// be called on the thread that owns the GL context, after drawing and before
+// the buffers are swapped.
+func readFrame(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	// OpenGL's rows start at the bottom, an image's at the top.
+	row := make([]byte, img.Stride)
+	for top, bottom := 0, height-1; top < bottom; top, bottom = top+1, bottom-1 {
+		t := img.Pix[top*img.Stride : (top+1)*img.Stride]
+		b := img.Pix[bottom*img.Stride : (bottom+1)*img.Stride]
+		copy(row, t)
+		copy(t, b)
+		copy(b, row)
+	}
+
+	// The window is shown opaque whatever alpha ends up in the framebuffer.
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+
+	return img
+}
+
+// saveScreenshot writes the current width by height framebuffer to path as a
+// PNG. Like readFrame it has to run on the GL thread.
+func saveScreenshot(width, height int, path string) error {
+	img := readFrame(width, height)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+// makeBoard builds a board set up from the command line flags and seeds it,
+// either from pattern or, if that's nil, randomly.
+func makeBoard(rows, columns int, threshold float64, seed int64, pattern [][]bool, r life.Rule) *life.Board {
+	board := life.NewBoard(rows, columns, r)
+	board.Wrap = *wrap
+	board.CornerWrap = *cornerWrap
+	board.Twist = *twist
+	if *grow {
+		// Growing stands in for an unbounded field, which has no edges to
+		// wrap around.
+		board.Wrap = false
+	}
+	switch {
+	case *hexGrid:
+		board.Neighborhood = life.Hexagonal
+	case *neighborhood == neighborhoodVonNeumann:
+		board.Neighborhood = life.VonNeumann
+	}
+	board.States = *states
+	board.Immigration = *immigration
+	board.Ecosystem = *mode == modeEcosystem
+	board.EnergyRegen = *energyRegen
+	board.EnergyCost = *energyCost
+	if *morphGens > 0 {
+		board.MorphRule, board.MorphGenerations, board.MorphSeed = morphRule, *morphGens, seed
+	}
+
+	// Which cells start alive and what color they are come from separate
+	// generators, so a change to how colors are picked can never change the
+	// starting board. Both use explicit sources: the global generator's Seed
+	// is a no-op on newer Go releases.
+	lifeRand := rand.New(rand.NewSource(seed))
+	colorRand := rand.New(rand.NewSource(^seed))
+
+	cells := board.Cells
+	for x := range cells {
+		for _, c := range cells[x] {
+			if pattern == nil && *seedStyle == seedStyleUniform {
+				c.Set(lifeRand.Float64() < threshold)
+			}
+			c.Color = cellPalette.sample(colorRand)
+		}
+	}
+
+	switch {
+	case pattern != nil:
+		if clipped := stampPattern(board, pattern); clipped > 0 {
+			logWarnf("pattern doesn't fit the %dx%d grid, %d live cells clipped", rows, columns, clipped)
+		}
+	case *seedStyle == seedStyleCluster:
+		seedClusters(cells, lifeRand)
+	case *seedStyle == seedStyleRadial:
+		seedDensity(cells, lifeRand, radialDensity(len(cells), len(cells[0]), threshold))
+	case *seedStyle == seedStyleNoise:
+		seedDensity(cells, lifeRand, noiseDensity(len(cells), len(cells[0]), threshold, *noiseScale, lifeRand))
+	}
+	if *mode == modeSmooth {
+		seedSmooth(cells, threshold, lifeRand)
+	}
+	if wildCardPattern != nil {
+		board.OverrideRule = overrideRule
+		if clipped := placeWildCards(board, wildCardPattern); clipped > 0 {
+			logWarnf("wild cards don't fit the %dx%d grid, %d clipped", rows, columns, clipped)
+		}
+	}
+
+	// Teams are picked after every color, so turning on immigration leaves
+	// the colors as they were.
+	if *immigration {
+		for x := range cells {
+			for _, c := range cells[x] {
+				c.SetTeam(colorRand.Intn(2))
+			}
+		}
+	}
+
+	return board
+}
+
+// randomColor picks a random opaque color for a cell, with each channel at
+// least 0.2 so it never gets lost against the background.
+func randomColor(r *rand.Rand) [4]float32 {
+	var min float32
+	min = 0.2
+	genColor := func() float32 {
+		c := r.Float32()
+		if c < min {
+			c = min
+		}
+		return c
+	}
+
+	return [4]float32{
+		genColor(),
+		genColor(),
+		genColor(),
+		1,
+	}
+}
+
+// seedClusters scatters small dense blobs across the board rather than giving
+// every cell the same chance of life. The board is split into tiles of
+// blobSpacing cells and each tile gets one blob at a random offset within it.
+// These tend to run far longer than uniform noise, which mostly dies off.
+func seedClusters(cells [][]*life.Cell, r *rand.Rand) {
+	for bx := 0; bx < len(cells); bx += *blobSpacing {
+		for by := 0; by < len(cells[bx]); by += *blobSpacing {
+			ox := bx + r.Intn(*blobSpacing)
+			oy := by + r.Intn(*blobSpacing)
+
+			for dx := 0; dx < *blobSize; dx++ {
+				for dy := 0; dy < *blobSize; dy++ {
+					x := (ox + dx) % len(cells)
+					y := (oy + dy) % len(cells[x])
+
+					if r.Float64() < clusterDensity {
+						cells[x][y].Set(true)
+					}
+				}
+			}
+		}
+	}
+}
+
+// seedDensity brings each cell to life with the chance density gives for its
+// position.
+func seedDensity(cells [][]*life.Cell, r *rand.Rand, density func(x, y int) float64) {
+	for x := range cells {
+		for y, c := range cells[x] {
+			c.Set(r.Float64() < density(x, y))
+		}
+	}
+}
+
+// radialDensity returns a density that falls off in a straight line from
+// twice threshold at the center of a rows by columns board to nothing at its
+// corners.
+//
+// Distances are worked out from their squares with math.Sqrt, which rounds
+// the same everywhere, rather than math.Hypot, which has assembly versions
+// that don't. The float64 conversions stop the squares being fused into a
+// multiply-add on platforms that have one. Either would let the same seed
+// grow a different board on another machine.
+func radialDensity(rows, columns int, threshold float64) func(x, y int) float64 {
+	cx, cy := float64(rows-1)/2, float64(columns-1)/2
+	farthest := float64(cx*cx) + float64(cy*cy)
+
+	return func(x, y int) float64 {
+		if farthest == 0 {
+			return threshold
+		}
+		dx, dy := float64(x)-cx, float64(y)-cy
+		d := math.Sqrt((float64(dx*dx) + float64(dy*dy)) / farthest)
+		return math.Min(1, 2*threshold*(1-d))
+	}
+}
+
+// noiseDensity returns a density made of value noise: random values on a
+// lattice scale cells apart, smoothly blended between. It ranges from nothing
+// to twice threshold, averaging around threshold.
+func noiseDensity(rows, columns int, threshold float64, scale int, r *rand.Rand) func(x, y int) float64 {
+	lattice := make([][]float64, rows/scale+2)
+	for i := range lattice {
+		lattice[i] = make([]float64, columns/scale+2)
+		for j := range lattice[i] {
+			lattice[i][j] = r.Float64()
+		}
+	}
+
+	// smooth eases between lattice points so the patches have no creases.
+	// Here and in lerp each product is converted to round it on its own,
+	// as a fused multiply-add would come out differently on arm64 than on
+	// amd64 for the same seed.
+	smooth := func(t float64) float64 { return t * t * (3 - float64(2*t)) }
+	lerp := func(a, b, t float64) float64 { return a + float64((b-a)*t) }
+
+	return func(x, y int) float64 {
+		i, j := x/scale, y/scale
+		tx := smooth(float64(x%scale) / float64(scale))
+		ty := smooth(float64(y%scale) / float64(scale))
+
+		n := lerp(
+			lerp(lattice[i][j], lattice[i+1][j], tx),
+			lerp(lattice[i][j+1], lattice[i+1][j+1], tx),
+			ty,
+		)
+		return math.Min(1, 2*threshold*n)
+	}
+}
+
+// population returns the number of live cells on the board. In smooth mode a
+// cell counts as alive once its state passes one half.
+func population(board *life.Board) int {
+	if *mode != modeSmooth {
+		return board.Population()
+	}
+
+	var count int
+	for x := range board.Cells {
+		for _, c := range board.Cells[x] {
+			if c.State >= 0.5 {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+var (
+	httpAddr = flag.String("http", "", "serve the run's statistics on this address, such as localhost:8080: JSON at /stats and Prometheus metrics at /metrics")
+)
+
+// detectedEvents are the events counted by a statsCollector, in the order
+// they're listed. Population milestones follow from the population and
+// aren't counted.
+var detectedEvents = []string{eventExtinct, eventStable, eventOscillator, eventSpaceship, eventPattern}
+
+// liveStats are the statistics a statsCollector serves.
+type liveStats struct {
+	Generation int `json:"generation"`
+	Population int `json:"population"`
+
+	// Births and Deaths are those of the last generation, TotalBirths and
+	// TotalDeaths those since the run started, across resets.
+	Births      int `json:"births"`
+	Deaths      int `json:"deaths"`
+	TotalBirths int `json:"total_births"`
+	TotalDeaths int `json:"total_deaths"`
+
+	// Events counts each type of detected event since the run started.
+	Events map[string]int `json:"events"`
+}
+
+// statsCollector keeps the statistics of the game being stepped for the
+// HTTP server, which reads them from its own goroutines.
+type statsCollector struct {
+	mu    sync.Mutex
+	stats liveStats
+}
+
+// newStatsCollector returns a collector with every event counted at 0.
+func newStatsCollector() *statsCollector {
+	c := &statsCollector{stats: liveStats{Events: map[string]int{}}}
+	for _, typ := range detectedEvents {
+		c.stats.Events[typ] = 0
+	}
+	return c
+}
+
+// start records a board starting, or starting over, with live cells. The
+// totals carry on from the board before.
+func (c *statsCollector) start(live int) {
+	c.record(0, live, 0, 0)
+}
+
+// record records a generation.
+func (c *statsCollector) record(generation, population, births, deaths int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Generation, c.stats.Population = generation, population
+	c.stats.Births, c.stats.Deaths = births, deaths
+	c.stats.TotalBirths += births
+	c.stats.TotalDeaths += deaths
+}
+
+// event counts an event, if it's one of detectedEvents.
+func (c *statsCollector) event(typ string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.stats.Events[typ]; ok {
+		c.stats.Events[typ]++
+	}
+}
+
+// snapshot returns a copy of the statistics.
+func (c *statsCollector) snapshot() liveStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Events = make(map[string]int, len(c.stats.Events))
+	for typ, n := range c.stats.Events {
+		s.Events[typ] = n
+	}
+	return s
+}
+
+// serveStats writes the statistics as JSON.
+func (c *statsCollector) serveStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c.snapshot())
+}
+
+// serveMetrics writes the statistics in the Prometheus text exposition
+// format. What describes the board now is a gauge, which a reset can take
+// back down, and what adds up over the run is a counter.
+func (c *statsCollector) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	s := c.snapshot()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	metric := func(name, typ, help string, value int) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, typ, name, value)
+	}
+	metric("gol_generation", "gauge", "Generation of the board, counted from its start.", s.Generation)
+	metric("gol_population", "gauge", "Live cells on the board.", s.Population)
+	metric("gol_births_total", "counter", "Cells born since the run started.", s.TotalBirths)
+	metric("gol_deaths_total", "counter", "Cells that died since the run started.", s.TotalDeaths)
+
+	fmt.Fprint(w, "# HELP gol_events_total Events detected since the run started, by type.\n# TYPE gol_events_total counter\n")
+	for _, typ := range detectedEvents {
+		fmt.Fprintf(w, "gol_events_total{type=%q} %d\n", typ, s.Events[typ])
+	}
+}
+
+// serveHTTP serves the collector's statistics on addr until the program
+// exits. Failing to listen is returned, so a bad address stops the run
+// before it starts.
+func serveHTTP(addr string, c *statsCollector) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", c.serveStats)
+	mux.HandleFunc("/metrics", c.serveMetrics)
+	go func() {
+		if err := http.Serve(l, mux); err != nil {
+			logErrorf("Stats server stopped: %v", err)
+		}
+	}()
+	logInfof("Serving stats on http://%s/stats and /metrics", l.Addr())
+	return nil
+}
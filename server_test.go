@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatsCollector(t *testing.T) {
+	// A block with a lone cell beside it, which dies, leaving the block
+	// stable.
+	g := newGame(newTestBoard(10, 10, mustParseRLE("x = 5, y = 2\n2o2bo$2o!"), 3, 3), nil, nil, nil, nil)
+	c := newStatsCollector()
+	g.collect(c)
+	g.Step()
+	g.Step()
+
+	rec := httptest.NewRecorder()
+	c.serveStats(rec, httptest.NewRequest("GET", "/stats", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("/stats Content-Type = %q, want application/json", ct)
+	}
+	var got liveStats
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("/stats isn't JSON: %v", err)
+	}
+	if got.Generation != 2 || got.Population != 4 || got.TotalBirths != 0 || got.TotalDeaths != 1 {
+		t.Errorf("/stats = %+v, want generation 2, population 4, no births and a death", got)
+	}
+	if got.Events[eventStable] != 1 || got.Events[eventExtinct] != 0 {
+		t.Errorf("/stats events = %v, want the board stable once", got.Events)
+	}
+
+	rec = httptest.NewRecorder()
+	c.serveMetrics(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("/metrics Content-Type = %q, want the Prometheus text format", ct)
+	}
+	want := `# HELP gol_generation Generation of the board, counted from its start.
+# TYPE gol_generation gauge
+gol_generation 2
+# HELP gol_population Live cells on the board.
+# TYPE gol_population gauge
+gol_population 4
+# HELP gol_births_total Cells born since the run started.
+# TYPE gol_births_total counter
+gol_births_total 0
+# HELP gol_deaths_total Cells that died since the run started.
+# TYPE gol_deaths_total counter
+gol_deaths_total 1
+# HELP gol_events_total Events detected since the run started, by type.
+# TYPE gol_events_total counter
+gol_events_total{type="extinct"} 0
+gol_events_total{type="stable"} 1
+gol_events_total{type="oscillator"} 0
+gol_events_total{type="spaceship"} 0
+gol_events_total{type="pattern"} 0
+`
+	if body := rec.Body.String(); body != want {
+		t.Errorf("/metrics =\n%s\nwant\n%s", body, want)
+	}
+}
+
+func TestStatsCollectorReset(t *testing.T) {
+	g := newGame(newTestBoard(10, 10, mustParseRLE("x = 3, y = 1\n3o!"), 3, 3), nil, nil, nil, nil)
+	c := newStatsCollector()
+	g.collect(c)
+	g.Step()
+	g.reset(newTestBoard(10, 10, mustParseRLE("x = 1, y = 1\no!"), 3, 3))
+
+	// The board's gauges start over, the run's counters don't.
+	s := c.snapshot()
+	if s.Generation != 0 || s.Population != 1 || s.TotalBirths != 2 || s.TotalDeaths != 2 {
+		t.Errorf("after a reset, stats = %+v, want generation 0, population 1 and the blinker's 2 births and deaths", s)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"math"
+	"math/rand"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	smoothRadius = flag.Float64("smooth-radius", 4, "outer radius of the smooth neighborhood, in cells")
+	smoothB1     = flag.Float64("smooth-b1", 0.278, "lower birth threshold for the outer filling")
+	smoothB2     = flag.Float64("smooth-b2", 0.365, "upper birth threshold for the outer filling")
+	smoothD1     = flag.Float64("smooth-d1", 0.267, "lower survival threshold for the outer filling")
+	smoothD2     = flag.Float64("smooth-d2", 0.445, "upper survival threshold for the outer filling")
+	smoothAlphaN = flag.Float64("smooth-alphan", 0.028, "steepness of the outer filling transition")
+	smoothAlphaM = flag.Float64("smooth-alpham", 0.147, "steepness of the inner filling transition")
+	smoothDt     = flag.Float64("smooth-dt", 0.1, "time step applied to each smooth update")
+)
+
+// smoothTap is a single offset of the smooth neighborhood kernel, weighted by
+// how much of it falls in the inner disk and in the outer ring.
+type smoothTap struct {
+	dx, dy int
+
+	inner float64
+	outer float64
+}
+
+// smoothKernel is the weighted neighborhood used by the smooth mode. The inner
+// disk has a third of the outer radius, and cells straddling either edge are
+// weighted by how far over the edge they are, which keeps the kernel round on
+// a coarse grid.
+type smoothKernel struct {
+	taps []smoothTap
+
+	innerArea float64
+	outerArea float64
+
+	// next holds each cell's next state while a step is worked out.
+	next [][]float64
+}
+
+// newSmoothKernel builds the kernel for the given outer radius.
+func newSmoothKernel(radius float64) *smoothKernel {
+	k := &smoothKernel{}
+	innerRadius := radius / 3
+
+	clamp := func(v float64) float64 {
+		return math.Max(0, math.Min(1, v))
+	}
+
+	reach := int(math.Ceil(radius + 0.5))
+	for dx := -reach; dx <= reach; dx++ {
+		for dy := -reach; dy <= reach; dy++ {
+			dist := math.Hypot(float64(dx), float64(dy))
+			inner := clamp(innerRadius + 0.5 - dist)
+			outer := clamp(radius+0.5-dist) - inner
+			if inner == 0 && outer == 0 {
+				continue
+			}
+
+			k.taps = append(k.taps, smoothTap{dx: dx, dy: dy, inner: inner, outer: outer})
+			k.innerArea += inner
+			k.outerArea += outer
+		}
+	}
+
+	return k
+}
+
+// filling returns the weighted average state of the inner disk (m) and the
+// outer ring (n) around the cell at x, y. The edges wrap the way the board's
+// do: onto a torus, twisted or not, or onto nothing if the board doesn't
+// wrap, where the neighborhood beyond the edge counts as dead.
+func (k *smoothKernel) filling(board *life.Board, x, y int) (m, n float64) {
+	rows, columns := board.Rows(), board.Columns()
+	for _, t := range k.taps {
+		nx, ny := x+t.dx, y+t.dy
+		if !board.Wrap && (nx < 0 || nx >= rows || ny < 0 || ny >= columns) {
+			continue
+		}
+
+		// Each time around the rows shifts the columns by the twist.
+		wraps := floorDiv(nx, rows)
+		nx -= wraps * rows
+		ny = ((ny+wraps*board.Twist)%columns + columns) % columns
+
+		s := board.Cells[nx][ny].State
+		m += s * t.inner
+		n += s * t.outer
+	}
+
+	return m / k.innerArea, n / k.outerArea
+}
+
+// floorDiv returns a divided by b, rounded down rather than towards zero.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// sigmoid is a smooth step from 0 to 1 centered on a, with a width of alpha.
+func sigmoid(x, a, alpha float64) float64 {
+	return 1 / (1 + math.Exp(-(x-a)*4/alpha))
+}
+
+// smoothTransition returns the target state for a cell with inner filling m
+// and outer filling n. It is the smooth counterpart of Conway's rules: the
+// birth interval applies while the cell is mostly dead, the survival interval
+// while it is mostly alive, and as alphaN and alphaM approach zero the
+// sigmoids become the hard thresholds of a discrete rule.
+func smoothTransition(m, n float64) float64 {
+	alive := sigmoid(m, 0.5, *smoothAlphaM)
+	lo := *smoothB1*(1-alive) + *smoothD1*alive
+	hi := *smoothB2*(1-alive) + *smoothD2*alive
+
+	return sigmoid(n, lo, *smoothAlphaN) * (1 - sigmoid(n, hi, *smoothAlphaN))
+}
+
+// stepSmooth advances every cell by one smooth time step. All of the next
+// states are computed from the current ones before any of them is committed.
+func stepSmooth(board *life.Board, k *smoothKernel) {
+	cells := board.Cells
+	if k.next == nil {
+		k.next = make([][]float64, len(cells))
+		for x := range cells {
+			k.next[x] = make([]float64, len(cells[x]))
+		}
+	}
+
+	for x := range cells {
+		for y, c := range cells[x] {
+			m, n := k.filling(board, x, y)
+			next := c.State + *smoothDt*(2*smoothTransition(m, n)-1)
+			k.next[x][y] = math.Max(0, math.Min(1, next))
+		}
+	}
+
+	for x := range cells {
+		for y, c := range cells[x] {
+			c.State = k.next[x][y]
+		}
+	}
+}
+
+// seedSmooth scatters square blobs the size of the smooth radius across the
+// board, enough of them to cover roughly threshold of it. Uniform noise is too
+// fine-grained for the smooth kernel and dies out straight away.
+func seedSmooth(cells [][]*life.Cell, threshold float64, r *rand.Rand) {
+	size := int(math.Max(1, *smoothRadius))
+	area := float64(len(cells) * len(cells[0]))
+	blobs := int(threshold * area / float64(size*size))
+
+	for i := 0; i < blobs; i++ {
+		bx, by := r.Intn(len(cells)), r.Intn(len(cells[0]))
+		for dx := 0; dx < size; dx++ {
+			for dy := 0; dy < size; dy++ {
+				x := (bx + dx) % len(cells)
+				y := (by + dy) % len(cells[x])
+				cells[x][y].State = 1
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+func TestSmoothTransitionDiscreteLimit(t *testing.T) {
+	// As the transitions sharpen, a mostly dead cell is born with the outer
+	// filling between b1 and b2, and a mostly live one survives with it
+	// between d1 and d2, the way a discrete rule's thresholds work.
+	defer func(n, m float64) { *smoothAlphaN, *smoothAlphaM = n, m }(*smoothAlphaN, *smoothAlphaM)
+	*smoothAlphaN, *smoothAlphaM = 1e-9, 1e-9
+
+	for _, m := range []float64{0, 0.2, 0.8, 1} {
+		lo, hi := *smoothB1, *smoothB2
+		if m > 0.5 {
+			lo, hi = *smoothD1, *smoothD2
+		}
+
+		// Stay clear of the thresholds themselves, where the sigmoids
+		// are at a half whatever their width.
+		for i := 0; i < 100; i++ {
+			n := (float64(i) + 0.25) / 100
+			want := 0.0
+			if n > lo && n < hi {
+				want = 1
+			}
+			if got := smoothTransition(m, n); math.Abs(got-want) > 1e-6 {
+				t.Errorf("smoothTransition(%g, %g) = %g, want %g", m, n, got, want)
+			}
+		}
+	}
+}
+
+func TestSmoothKernelArea(t *testing.T) {
+	// Weighting the cells on the edges by how far over they are keeps the
+	// areas close to those of a true disk and ring.
+	for _, radius := range []float64{4, 10, 20} {
+		k := newSmoothKernel(radius)
+		inner := math.Pi * radius * radius / 9
+		outer := math.Pi*radius*radius - inner
+		if math.Abs(k.innerArea-inner) > 0.1*inner {
+			t.Errorf("radius %g: inner area %g, want about %g", radius, k.innerArea, inner)
+		}
+		if math.Abs(k.outerArea-outer) > 0.1*outer {
+			t.Errorf("radius %g: outer area %g, want about %g", radius, k.outerArea, outer)
+		}
+	}
+}
+
+func TestSmoothFillingEdges(t *testing.T) {
+	tests := []struct {
+		name  string
+		wrap  bool
+		twist int
+		cell  [2]int
+		sees  bool
+	}{
+		{"wrapped", true, 0, [2]int{0, 5}, true},
+		{"bounded", false, 0, [2]int{0, 5}, false},
+
+		// Across a twisted seam the live cell is three columns down.
+		{"twisted", true, 3, [2]int{0, 8}, true},
+		{"twisted off the seam", true, 3, [2]int{0, 5}, false},
+	}
+
+	k := newSmoothKernel(2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := life.NewBoard(10, 10, conway)
+			b.Wrap = tt.wrap
+			b.Twist = tt.twist
+			b.Cells[9][5].State = 1
+
+			_, n := k.filling(b, tt.cell[0], tt.cell[1])
+			if (n > 0) != tt.sees {
+				t.Errorf("outer filling %g, want it to see the live cell: %v", n, tt.sees)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"hash/fnv"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	detectSpaceship = flag.Int("detect-spaceship", 0, "report when the live cells repeat their shape shifted across the board within this many generations, as a spaceship does (0 turns it off)")
+)
+
+// shape is a generation's live cells reduced to their shape, wherever it is
+// on the board: a hash of the cells relative to the bottom-left corner of
+// their bounding box, and where that corner is.
+type shape struct {
+	hash uint64
+	x    int
+	y    int
+}
+
+// spaceshipDetector notices when the live cells move across the board
+// keeping their shape, by comparing the shape of each generation with the
+// ones before it. Shapes that repeat in place are oscillators, left to
+// periodDetector.
+type spaceshipDetector struct {
+	// shapes is a ring buffer of recent shapes; next is where the next one
+	// goes and filled how many slots hold one.
+	shapes []shape
+	next   int
+	filled int
+
+	// reported is the period and displacement last logged, so a spaceship
+	// is only reported once.
+	reported [3]int
+}
+
+func newSpaceshipDetector(window int) *spaceshipDetector {
+	return &spaceshipDetector{shapes: make([]shape, window)}
+}
+
+// observe records the current generation and returns the shortest period,
+// up to the window, after which the live cells have the same shape moved by
+// dx, dy, or zero if they haven't. On a wrapping board the displacement is
+// the shortest way around it. A newly found spaceship is logged.
+func (d *spaceshipDetector) observe(board *life.Board) (period, dx, dy int) {
+	s, ok := shapeOf(board.Cells)
+	if !ok {
+		d.reset()
+		return 0, 0, 0
+	}
+
+	for k := 1; k <= d.filled; k++ {
+		prev := d.shapes[(d.next-k+len(d.shapes))%len(d.shapes)]
+		if prev.hash != s.hash {
+			continue
+		}
+
+		// The same shape in the same place is an oscillator.
+		if prev.x == s.x && prev.y == s.y {
+			break
+		}
+		period, dx, dy = k, s.x-prev.x, s.y-prev.y
+		if board.Wrap {
+			dx, dy = shortestWrap(dx, board.Rows()), shortestWrap(dy, board.Columns())
+		}
+		break
+	}
+
+	d.shapes[d.next] = s
+	d.next = (d.next + 1) % len(d.shapes)
+	if d.filled < len(d.shapes) {
+		d.filled++
+	}
+
+	if found := [3]int{period, dx, dy}; period > 0 && found != d.reported {
+		logResultf("spaceship: period %d, displacement (%d,%d)", period, dx, dy)
+	}
+	d.reported = [3]int{period, dx, dy}
+	return period, dx, dy
+}
+
+// reset forgets every generation seen so far, for a new board.
+func (d *spaceshipDetector) reset() {
+	d.next = 0
+	d.filled = 0
+	d.reported = [3]int{}
+}
+
+// shortestWrap returns d, a distance along an axis size cells around, as the
+// shortest distance either way around it.
+func shortestWrap(d, size int) int {
+	d %= size
+	if d > size/2 {
+		d -= size
+	} else if d < -size/2 {
+		d += size
+	}
+	return d
+}
+
+// shapeOf returns the shape of the live cells, or false if there are none.
+// The shape is found from the bounding box, so a pattern part way across the
+// edge of a wrapping board has a different shape until it's all the way over.
+func shapeOf(cells [][]*life.Cell) (shape, bool) {
+	minX, minY := -1, -1
+	for x := range cells {
+		for y, c := range cells[x] {
+			if !c.Alive() {
+				continue
+			}
+			if minX < 0 {
+				minX = x
+			}
+			if minY < 0 || y < minY {
+				minY = y
+			}
+		}
+	}
+	if minX < 0 {
+		return shape{}, false
+	}
+
+	h := fnv.New64a()
+	var buf [8]byte
+	for x := minX; x < len(cells); x++ {
+		for y, c := range cells[x] {
+			if c.Alive() {
+				binary.LittleEndian.PutUint32(buf[:4], uint32(x-minX))
+				binary.LittleEndian.PutUint32(buf[4:], uint32(y-minY))
+				h.Write(buf[:])
+			}
+		}
+	}
+	return shape{hash: h.Sum64(), x: minX, y: minY}, true
+}
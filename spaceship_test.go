@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSpaceshipDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern [][]bool
+		wrap    bool
+		want    [3]int // Period and displacement.
+	}{
+		{"glider", brushes["glider"], true, [3]int{4, 1, -1}},
+		{"glider bounded", brushes["glider"], false, [3]int{4, 1, -1}},
+		{"lwss", brushes["lwss"], true, [3]int{4, -2, 0}},
+
+		// Oscillators repeat in place and aren't spaceships.
+		{"blinker", mustParseRLE("x = 3, y = 1\n3o!"), true, [3]int{}},
+		{"block", mustParseRLE("x = 2, y = 2\n2o$2o!"), true, [3]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(16, 16, tt.pattern, 6, 6)
+			b.Wrap = tt.wrap
+			d := newSpaceshipDetector(8)
+			d.observe(b)
+
+			var got [3]int
+			for i := 0; i < 8; i++ {
+				b.Step()
+				period, dx, dy := d.observe(b)
+				got = [3]int{period, dx, dy}
+			}
+			if got != tt.want {
+				t.Errorf("observe = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpaceshipDetectorWrapped(t *testing.T) {
+	// Crossing the edge of a torus the displacement is still the short way
+	// round, every generation the shape is whole.
+	b := newTestBoard(12, 12, brushes["glider"], 6, 6)
+	d := newSpaceshipDetector(8)
+	d.observe(b)
+	for i := 0; i < 60; i++ {
+		b.Step()
+		if period, dx, dy := d.observe(b); period != 0 && [3]int{period, dx, dy} != [3]int{4, 1, -1} {
+			t.Fatalf("generation %d: observe = %d, (%d,%d), want 4, (1,-1)", i+1, period, dx, dy)
+		}
+	}
+}
+
+func TestShortestWrap(t *testing.T) {
+	tests := []struct{ d, size, want int }{
+		{1, 12, 1},
+		{-1, 12, -1},
+		{11, 12, -1},
+		{-11, 12, 1},
+		{6, 12, 6},
+		{13, 12, 1},
+	}
+	for _, tt := range tests {
+		if got := shortestWrap(tt.d, tt.size); got != tt.want {
+			t.Errorf("shortestWrap(%d, %d) = %d, want %d", tt.d, tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"flag"
+	"math"
+
+	"github.com/aculler/conway-gol/life"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+const (
+	spacetimeVertexShaderSource = `
+		#version 410
+		layout(location = 0) in vec3 vp;
+		layout(location = 1) in vec2 offset;
+		layout(location = 2) in vec4 color;
+
+		uniform mat4 mvp;
+		uniform float depth;
+
+		out vec4 cellColor;
+
+		void main() {
+			cellColor = color;
+			gl_Position = mvp * vec4(vp.xy + offset, depth, 1.0);
+		}
+` + "\x00"
+
+	// cameraDistance is how far the camera sits from the center of the stack.
+	cameraDistance = 4.5
+)
+
+var (
+	spacetimeLayers = flag.Int("spacetime-layers", 32, "generations stacked along the time axis in spacetime mode")
+)
+
+// mat4 is a 4x4 matrix in the column-major order OpenGL expects.
+type mat4 [16]float32
+
+func identity() mat4 {
+	return mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// mul returns m * n.
+func (m mat4) mul(n mat4) mat4 {
+	var r mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += m[k*4+row] * n[col*4+k]
+			}
+			r[col*4+row] = sum
+		}
+	}
+	return r
+}
+
+func perspective(fovy, aspect, near, far float32) mat4 {
+	f := float32(1 / math.Tan(float64(fovy)/2))
+
+	var m mat4
+	m[0] = f / aspect
+	m[5] = f
+	m[10] = (far + near) / (near - far)
+	m[11] = -1
+	m[14] = 2 * far * near / (near - far)
+	return m
+}
+
+func translate(x, y, z float32) mat4 {
+	m := identity()
+	m[12], m[13], m[14] = x, y, z
+	return m
+}
+
+func rotateX(angle float64) mat4 {
+	s, c := float32(math.Sin(angle)), float32(math.Cos(angle))
+
+	m := identity()
+	m[5], m[6] = c, s
+	m[9], m[10] = -s, c
+	return m
+}
+
+func rotateY(angle float64) mat4 {
+	s, c := float32(math.Sin(angle)), float32(math.Cos(angle))
+
+	m := identity()
+	m[0], m[2] = c, -s
+	m[8], m[10] = s, c
+	return m
+}
+
+// spacetime renders the last few generations stacked along the Z axis, so a
+// moving pattern leaves a trail through time. The newest generation is at the
+// front of the stack and older ones fade out behind it.
+type spacetime struct {
+	program  uint32
+	renderer *cellRenderer
+
+	aspect float32
+
+	mvpLocation   int32
+	depthLocation int32
+
+	// history is a ring buffer of past boards; next is where the next
+	// generation will be recorded and filled how many slots hold one.
+	history [][][]bool
+	next    int
+	filled  int
+
+	yaw   float64
+	pitch float64
+
+	dragging bool
+	lastX    float64
+	lastY    float64
+}
+
+func newSpacetime(renderer *cellRenderer, layers int, aspect float32) (*spacetime, error) {
+	program, err := makeProgram(spacetimeVertexShaderSource, cellFragmentShaderSource)
+	if err != nil {
+		return nil, err
+	}
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+
+	return &spacetime{
+		program:  program,
+		renderer: renderer,
+		aspect:   aspect,
+
+		mvpLocation:   gl.GetUniformLocation(program, gl.Str("mvp\x00")),
+		depthLocation: gl.GetUniformLocation(program, gl.Str("depth\x00")),
+
+		history: make([][][]bool, layers),
+
+		// Start at an angle so the stack reads as 3D straight away.
+		yaw:   0.6,
+		pitch: 0.4,
+	}, nil
+}
+
+// delete frees the spacetime view's program.
+func (s *spacetime) delete() {
+	gl.DeleteProgram(s.program)
+}
+
+// attach lets the view be orbited by dragging with the left mouse button.
+func (s *spacetime) attach(window *glfw.Window) {
+	window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		if button != glfw.MouseButtonLeft {
+			return
+		}
+
+		s.dragging = action == glfw.Press
+		s.lastX, s.lastY = w.GetCursorPos()
+	})
+
+	window.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		if !s.dragging {
+			return
+		}
+
+		s.yaw += (x - s.lastX) * 0.01
+		s.pitch += (y - s.lastY) * 0.01
+		s.pitch = math.Max(-1.5, math.Min(1.5, s.pitch))
+		s.lastX, s.lastY = x, y
+	})
+}
+
+// record pushes the current generation onto the history, dropping the oldest
+// one once the buffer is full.
+func (s *spacetime) record(cells [][]*life.Cell) {
+	board := s.history[s.next]
+	if board == nil {
+		board = make([][]bool, len(cells))
+		for x := range cells {
+			board[x] = make([]bool, len(cells[x]))
+		}
+		s.history[s.next] = board
+	}
+
+	for x := range cells {
+		for y, c := range cells[x] {
+			board[x][y] = c.Alive()
+		}
+	}
+
+	s.next = (s.next + 1) % len(s.history)
+	if s.filled < len(s.history) {
+		s.filled++
+	}
+}
+
+// reset clears the history for a new board.
+func (s *spacetime) reset() {
+	s.next = 0
+	s.filled = 0
+}
+
+func (s *spacetime) draw(cells [][]*life.Cell) {
+	gl.UseProgram(s.program)
+
+	mvp := perspective(math.Pi/4, s.aspect, 0.1, 100).
+		mul(translate(0, 0, -cameraDistance)).
+		mul(rotateX(s.pitch)).
+		mul(rotateY(s.yaw))
+	gl.UniformMatrix4fv(s.mvpLocation, 1, false, &mvp[0])
+
+	// The layers are translucent, so they're drawn back to front instead of
+	// relying on the depth buffer. The stack runs along Z, which means the
+	// order only depends on which side of it the camera is on.
+	spacing := 2 / float32(len(s.history))
+	behind := math.Cos(s.yaw)*math.Cos(s.pitch) < 0
+	for i := 0; i < s.filled; i++ {
+		age := s.filled - 1 - i
+		if behind {
+			age = i
+		}
+
+		board := s.history[(s.next-1-age+len(s.history))%len(s.history)]
+		alpha := 1 - 0.9*float32(age)/float32(len(s.history))
+		gl.Uniform1f(s.depthLocation, 1-float32(age)*spacing)
+
+		for x := range board {
+			for y, alive := range board[x] {
+				if !alive {
+					continue
+				}
+
+				c := cells[x][y]
+				s.renderer.add(x, y, [4]float32{c.Color[0], c.Color[1], c.Color[2], alpha})
+			}
+		}
+		s.renderer.flush()
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"os"
+	"strconv"
+	"time"
+)
+
+// statsFlushInterval is the most time recorded statistics wait in memory
+// before being written to the file.
+const statsFlushInterval = time.Second
+
+var (
+	statsFile = flag.String("stats", "", "write the generation, population, births and deaths of every generation to this CSV file")
+)
+
+// statsWriter records statistics about each generation as rows of a CSV file.
+type statsWriter struct {
+	f *os.File
+	w *csv.Writer
+
+	flushed time.Time
+
+	// err is the first error writing the file, after which it's left alone.
+	err error
+}
+
+// newStatsWriter creates the file at path and writes the header row.
+func newStatsWriter(path string) (*statsWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &statsWriter{f: f, w: csv.NewWriter(f), flushed: time.Now()}
+	if err := s.w.Write([]string{"generation", "population", "births", "deaths"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// record adds a row for a generation. Rows are flushed to the file every
+// statsFlushInterval. If writing them fails, that's logged and nothing more
+// is recorded.
+func (s *statsWriter) record(generation, population, births, deaths int) {
+	if s.err != nil {
+		return
+	}
+
+	s.w.Write([]string{
+		strconv.Itoa(generation),
+		strconv.Itoa(population),
+		strconv.Itoa(births),
+		strconv.Itoa(deaths),
+	})
+
+	if time.Since(s.flushed) < statsFlushInterval {
+		return
+	}
+	s.flushed = time.Now()
+	s.w.Flush()
+	if s.err = s.w.Error(); s.err != nil {
+		logErrorf("Failed to write stats: %v", s.err)
+	}
+}
+
+// close flushes any rows still waiting and closes the file.
+func (s *statsWriter) close() {
+	if s.err == nil {
+		s.w.Flush()
+		if err := s.w.Error(); err != nil {
+			logErrorf("Failed to write stats: %v", err)
+		}
+	}
+	s.f.Close()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestStatsWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.csv")
+	stats, err := newStatsWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A glider keeps its population, with every birth matched by a death.
+	g := newGame(newTestBoard(10, 10, brushes["glider"], 3, 3), nil, nil, stats, nil)
+	for i := 0; i < 4; i++ {
+		g.Step()
+	}
+	stats.close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 6 {
+		t.Fatalf("got %d rows, want a header and 5 generations", len(rows))
+	}
+	if header := rows[0]; header[0] != "generation" || header[1] != "population" || header[2] != "births" || header[3] != "deaths" {
+		t.Errorf("header = %v", header)
+	}
+	for i, row := range rows[1:] {
+		if row[0] != strconv.Itoa(i) || row[1] != "5" || row[2] != row[3] {
+			t.Errorf("row %d = %v, want generation %d with population 5 and births matching deaths", i+1, row, i)
+		}
+		if i > 0 && row[2] == "0" {
+			t.Errorf("row %d = %v, want some births", i+1, row)
+		}
+	}
+}
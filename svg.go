@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+const (
+	// svgCellSize is the width and height of each cell in an SVG, in pixels.
+	svgCellSize = 10
+
+	// maxSVGGens is the most generations -svganim animates. Every cell that
+	// is ever alive gets its own animation with a value per generation, so
+	// the file and the browser's work grow with both.
+	maxSVGGens = 500
+)
+
+var (
+	svgAnimFile = flag.String("svganim", "", "run -svggens generations without a window and save them to this file as an SVG that animates through them in a loop, then exit")
+	svgGens     = flag.Int("svggens", 50, fmt.Sprintf("how many generations -svganim animates, at most %d", maxSVGGens))
+)
+
+// writeSVGHeader opens an SVG of a rows by columns board and fills it with
+// the background color.
+func writeSVGHeader(w *bufio.Writer, rows, columns int) {
+	width, height := rows*svgCellSize, columns*svgCellSize
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	fmt.Fprintf(w, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height, hexColor(backgroundColor))
+}
+
+// svgRect returns the attributes placing the cell at x, y on a board
+// columns cells tall. SVG's y runs down, the board's up.
+func svgRect(x, y, columns int) string {
+	return fmt.Sprintf("x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"", x*svgCellSize, (columns-1-y)*svgCellSize, svgCellSize, svgCellSize)
+}
+
+// writeSVG writes the board as an SVG with a square for each cell drawn, in
+// the color it is drawn in.
+func writeSVG(out io.Writer, cells [][]*life.Cell) error {
+	w := bufio.NewWriter(out)
+	columns := len(cells[0])
+	writeSVGHeader(w, len(cells), columns)
+	for x := range cells {
+		for y, c := range cells[x] {
+			color, ok := drawColor(c)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "<rect %s fill=\"%s\" fill-opacity=\"%.3g\"/>\n", svgRect(x, y, columns), hexColor(color), color[3])
+		}
+	}
+	w.WriteString("</svg>\n")
+	return w.Flush()
+}
+
+// saveSVG writes the board to path as an SVG.
+func saveSVG(cells [][]*life.Cell, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeSVG(f, cells); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeSVGAnim writes frames, snapshots of the board one generation apart,
+// as an SVG that shows each for interval and then starts over. Every cell
+// alive in any frame is a square in its color from fills, with its fill
+// opacity switching between 1 and 0 as it lives and dies. All the
+// animations share a duration and repeat forever, so they stay in step and
+// the last frame is followed by the first.
+func writeSVGAnim(out io.Writer, frames [][][]bool, fills [][][4]float32, interval time.Duration) error {
+	w := bufio.NewWriter(out)
+	rows, columns := len(frames[0]), len(frames[0][0])
+	dur := fmt.Sprintf("%gs", (time.Duration(len(frames)) * interval).Seconds())
+	writeSVGHeader(w, rows, columns)
+
+	var values strings.Builder
+	for x := 0; x < rows; x++ {
+		for y := 0; y < columns; y++ {
+			values.Reset()
+			everAlive := false
+			for i, frame := range frames {
+				if i > 0 {
+					values.WriteByte(';')
+				}
+				if frame[x][y] {
+					values.WriteByte('1')
+					everAlive = true
+				} else {
+					values.WriteByte('0')
+				}
+			}
+			if !everAlive {
+				continue
+			}
+
+			// Discrete values split the duration evenly, so each frame
+			// gets one interval.
+			fmt.Fprintf(w, "<rect %s fill=\"%s\" fill-opacity=\"%c\">", svgRect(x, y, columns), hexColor(fills[x][y]), values.String()[0])
+			fmt.Fprintf(w, "<animate attributeName=\"fill-opacity\" values=\"%s\" dur=\"%s\" calcMode=\"discrete\" repeatCount=\"indefinite\"/>", values.String(), dur)
+			w.WriteString("</rect>\n")
+		}
+	}
+	w.WriteString("</svg>\n")
+	return w.Flush()
+}
+
+// runSVGAnim steps the game through -svggens generations, counting the
+// starting board, and saves them to path as an animated SVG. Each cell is
+// filled with the color it was first drawn in while alive.
+func runSVGAnim(ctx context.Context, g *Game, path string) error {
+	cells := g.board.Cells
+	fills := make([][][4]float32, len(cells))
+	for x := range cells {
+		fills[x] = make([][4]float32, len(cells[x]))
+	}
+	seen := snapshot(cells)
+	for x := range cells {
+		for y, c := range cells[x] {
+			if seen[x][y] {
+				fills[x][y], _ = drawColor(c)
+			}
+		}
+	}
+
+	frames := [][][]bool{snapshot(cells)}
+	for len(frames) < *svgGens {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		g.Step()
+		frame := snapshot(cells)
+		for x := range cells {
+			for y, c := range cells[x] {
+				if frame[x][y] && !seen[x][y] {
+					seen[x][y] = true
+					fills[x][y], _ = drawColor(c)
+				}
+			}
+		}
+		frames = append(frames, frame)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeSVGAnim(f, frames, fills, frameInterval()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
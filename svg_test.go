@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// svgRectElement is a rect of an SVG, with its animation if it has one.
+type svgRectElement struct {
+	X           int    `xml:"x,attr"`
+	Y           int    `xml:"y,attr"`
+	FillOpacity string `xml:"fill-opacity,attr"`
+	Animate     *struct {
+		AttributeName string `xml:"attributeName,attr"`
+		Values        string `xml:"values,attr"`
+		Dur           string `xml:"dur,attr"`
+		CalcMode      string `xml:"calcMode,attr"`
+		RepeatCount   string `xml:"repeatCount,attr"`
+	} `xml:"animate"`
+}
+
+// parseSVG decodes data as an SVG, failing the test if it isn't well-formed.
+func parseSVG(t *testing.T, data []byte) []svgRectElement {
+	t.Helper()
+	var svg struct {
+		XMLName xml.Name         `xml:"http://www.w3.org/2000/svg svg"`
+		Rects   []svgRectElement `xml:"rect"`
+	}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = true
+	if err := d.Decode(&svg); err != nil {
+		t.Fatalf("output isn't well-formed SVG: %v\n%s", err, data)
+	}
+	return svg.Rects
+}
+
+func TestWriteSVG(t *testing.T) {
+	g := newGame(newTestBoard(5, 5, mustParseRLE("x = 3, y = 1\n3o!"), 1, 2), nil, nil, nil, nil)
+
+	var buf bytes.Buffer
+	if err := writeSVG(&buf, g.board.Cells); err != nil {
+		t.Fatal(err)
+	}
+	rects := parseSVG(t, buf.Bytes())
+
+	// The background, then a square for each live cell.
+	if len(rects) != 4 {
+		t.Fatalf("got %d rects, want 4", len(rects))
+	}
+	for i, r := range rects[1:] {
+		if want := (1 + i) * svgCellSize; r.X != want || r.Y != 2*svgCellSize {
+			t.Errorf("cell %d at %d,%d, want %d,%d", i, r.X, r.Y, want, 2*svgCellSize)
+		}
+	}
+}
+
+func TestRunSVGAnim(t *testing.T) {
+	defer func(v int) { *svgGens = v }(*svgGens)
+	*svgGens = 4
+
+	// A vertical blinker, turning horizontal and back.
+	g := newGame(newTestBoard(5, 5, mustParseRLE("x = 1, y = 3\no$o$o!"), 2, 1), nil, nil, nil, nil)
+	path := filepath.Join(t.TempDir(), "blinker.svg")
+	if err := runSVGAnim(context.Background(), g, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rects := parseSVG(t, data)
+
+	want := map[[2]int]string{
+		{2, 1}: "1;0;1;0",
+		{2, 2}: "1;1;1;1",
+		{2, 3}: "1;0;1;0",
+		{1, 2}: "0;1;0;1",
+		{3, 2}: "0;1;0;1",
+	}
+	if len(rects) != len(want)+1 {
+		t.Fatalf("got %d rects, want the background and %d cells", len(rects), len(want))
+	}
+	dur := fmt.Sprintf("%gs", (4 * frameInterval()).Seconds())
+	for _, r := range rects[1:] {
+		x, y := r.X/svgCellSize, 4-r.Y/svgCellSize
+		a := r.Animate
+		if a == nil {
+			t.Errorf("cell %d,%d isn't animated", x, y)
+			continue
+		}
+		if a.Values != want[[2]int{x, y}] {
+			t.Errorf("cell %d,%d values = %q, want %q", x, y, a.Values, want[[2]int{x, y}])
+		}
+		if a.AttributeName != "fill-opacity" || a.CalcMode != "discrete" || a.Dur != dur {
+			t.Errorf("cell %d,%d animates %s %s over %s, want fill-opacity discrete over %s", x, y, a.AttributeName, a.CalcMode, a.Dur, dur)
+		}
+
+		// The animation loops back to the first frame, which the cell
+		// also shows before it starts.
+		if a.RepeatCount != "indefinite" {
+			t.Errorf("cell %d,%d repeats %q times, want indefinite", x, y, a.RepeatCount)
+		}
+		if first := strings.SplitN(a.Values, ";", 2)[0]; r.FillOpacity != first {
+			t.Errorf("cell %d,%d starts at opacity %s, want its first frame's %s", x, y, r.FillOpacity, first)
+		}
+	}
+}
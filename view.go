@@ -0,0 +1,98 @@
+package main
+
+const (
+	// maxZoom is how far in the view can zoom, in times the size of the
+	// whole grid.
+	maxZoom = 64
+
+	// zoomStep is how much one notch of the scroll wheel zooms by.
+	zoomStep = 1.25
+
+	// panStep is how far one press of a pan key moves the view, as a
+	// fraction of the window.
+	panStep = 0.1
+)
+
+// view is the pan and zoom applied to the grid, in normalized device
+// coordinates: a point p on the grid is drawn at p*scale + offset. Zooming
+// out never goes past the whole grid, and panning never leaves it.
+type view struct {
+	scale   float32
+	offsetX float32
+	offsetY float32
+}
+
+func newView() *view {
+	return &view{scale: 1}
+}
+
+// matrix returns the view as a transform for the vertex shaders.
+func (v *view) matrix() mat4 {
+	m := translate(v.offsetX, v.offsetY, 0)
+	m[0], m[5] = v.scale, v.scale
+	return m
+}
+
+// zoom scales the view by factor, keeping the point at x, y where it is on
+// screen.
+func (v *view) zoom(factor, x, y float32) {
+	scale := v.scale * factor
+	if scale < 1 {
+		scale = 1
+	} else if scale > maxZoom {
+		scale = maxZoom
+	}
+
+	gx, gy := v.unproject(x, y)
+	v.scale = scale
+	v.offsetX = x - gx*scale
+	v.offsetY = y - gy*scale
+	v.clamp()
+}
+
+// pan moves the view across the grid by dx, dy window widths and heights.
+func (v *view) pan(dx, dy float32) {
+	v.offsetX -= dx * 2
+	v.offsetY -= dy * 2
+	v.clamp()
+}
+
+// reset shows the whole grid again.
+func (v *view) reset() {
+	*v = view{scale: 1}
+}
+
+// unproject returns the point on the grid drawn at x, y on screen.
+func (v *view) unproject(x, y float32) (gx, gy float32) {
+	return (x - v.offsetX) / v.scale, (y - v.offsetY) / v.scale
+}
+
+// clamp keeps the grid covering the whole screen.
+func (v *view) clamp() {
+	limit := v.scale - 1
+	clampOne := func(o float32) float32 {
+		if o < -limit {
+			return -limit
+		}
+		if o > limit {
+			return limit
+		}
+		return o
+	}
+	v.offsetX = clampOne(v.offsetX)
+	v.offsetY = clampOne(v.offsetY)
+}
+
+// toNDC converts a position in a width by height area, from its top left, to
+// normalized device coordinates.
+func toNDC(px, py float64, width, height int) (x, y float32) {
+	return float32(px/float64(width)*2 - 1), float32(1 - py/float64(height)*2)
+}
+
+// unprojectWindow is unproject for a position in a width by height area of the
+// window, from its top left, returning where on the whole, unzoomed grid
+// drawn in that area it falls.
+func (v *view) unprojectWindow(px, py float64, width, height int) (float64, float64) {
+	gx, gy := v.unproject(toNDC(px, py, width, height))
+	return float64(gx+1) / 2 * float64(width), float64(1-gy) / 2 * float64(height)
+}
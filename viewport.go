@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"math"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// The values of -align.
+const (
+	alignCenter      = "center"
+	alignTop         = "top"
+	alignBottom      = "bottom"
+	alignLeft        = "left"
+	alignRight       = "right"
+	alignTopLeft     = "topleft"
+	alignTopRight    = "topright"
+	alignBottomLeft  = "bottomleft"
+	alignBottomRight = "bottomright"
+)
+
+var (
+	cellSize  = flag.Int("cell-size", 0, "size the window to give each cell this many pixels, in place of -width and -height (0 uses them)")
+	letterbox = flag.Bool("letterbox", false, "keep cells square when the window's shape doesn't match the grid's, leaving bars of background around it")
+	align     = flag.String("align", alignCenter, "where -letterbox puts the grid in the window: center, top, bottom, left, right, topleft, topright, bottomleft or bottomright")
+)
+
+// alignments are how far across and up the spare space -align puts the grid,
+// from 0 for the left or bottom to 2 for the right or top.
+var alignments = map[string][2]int{
+	alignCenter:      {1, 1},
+	alignTop:         {1, 2},
+	alignBottom:      {1, 0},
+	alignLeft:        {0, 1},
+	alignRight:       {2, 1},
+	alignTopLeft:     {0, 2},
+	alignTopRight:    {2, 2},
+	alignBottomLeft:  {0, 0},
+	alignBottomRight: {2, 0},
+}
+
+// validAlign reports whether name is one of the values of -align.
+func validAlign(name string) bool {
+	_, ok := alignments[name]
+	return ok
+}
+
+// gridViewport returns the part of a width by height area that a rows by
+// columns grid fills, with x and y its offset from the bottom left. That's
+// the whole area, stretching the cells to fit, unless -letterbox is set, when
+// it's the largest area with the grid's shape, placed by -align.
+func gridViewport(width, height, rows, columns int) (x, y, w, h int) {
+	if !*letterbox {
+		return 0, 0, width, height
+	}
+
+	// Rows run across the window and columns up it.
+	w, h = width, height
+	if width*columns > height*rows {
+		w = height * rows / columns
+	} else {
+		h = width * columns / rows
+	}
+
+	a := alignments[*align]
+	return (width - w) * a[0] / 2, (height - h) * a[1] / 2, w, h
+}
+
+// cellWindowSize returns the size of a window giving each cell of a rows by
+// columns grid size pixels, with room for every board tiled in it. If that
+// wouldn't fit on the primary monitor, the cells are shrunk until it does.
+// glfw has to be initialized.
+func cellWindowSize(rows, columns, size int) (width, height int) {
+	// Rows run across the window and columns up it.
+	w, h := float64(rows), float64(columns)
+	if *hexGrid {
+		w, h = float64(rows)+0.5, 0.75*float64(columns)+0.25
+	}
+	scale := float64(size)
+
+	across, down := tileLayout(numBoards())
+	gapX, gapY := float64((across-1)*tileGap), float64((down-1)*tileGap)
+	w, h = w*float64(across), h*float64(down)
+
+	if monitor := glfw.GetPrimaryMonitor(); monitor != nil {
+		mode := monitor.GetVideoMode()
+		fit := math.Min((float64(mode.Width)-gapX)/w, (float64(mode.Height)-gapY)/h)
+		if fit < scale {
+			logWarnf("A %dx%d grid at %d pixels a cell doesn't fit on the %dx%d screen", rows, columns, size, mode.Width, mode.Height)
+			scale = fit
+		}
+	}
+
+	width = int(math.Max(1, math.Floor(w*scale+gapX)))
+	height = int(math.Max(1, math.Floor(h*scale+gapY)))
+	logInfof("Window size %dx%d", width, height)
+	return width, height
+}
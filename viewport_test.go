@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestGridViewportAlign(t *testing.T) {
+	defer func(l bool, a string) { *letterbox, *align = l, a }(*letterbox, *align)
+	*letterbox = true
+
+	// A 10x20 grid fills the middle 100x200 of a 400x200 window, leaving
+	// 300 pixels to put to its left or right, and a 20x10 grid the middle
+	// 200x100 of a 200x400 window, leaving 300 above or below.
+	tests := []struct {
+		align string
+		wideX int
+		tallY int
+	}{
+		{alignCenter, 150, 150},
+		{alignTop, 150, 300},
+		{alignBottom, 150, 0},
+		{alignLeft, 0, 150},
+		{alignRight, 300, 150},
+		{alignTopLeft, 0, 300},
+		{alignTopRight, 300, 300},
+		{alignBottomLeft, 0, 0},
+		{alignBottomRight, 300, 0},
+	}
+	for _, tt := range tests {
+		*align = tt.align
+		if x, y, w, h := gridViewport(400, 200, 10, 20); x != tt.wideX || y != 0 || w != 100 || h != 200 {
+			t.Errorf("-align %s in a wide window: got %d,%d %dx%d, want %d,0 100x200", tt.align, x, y, w, h, tt.wideX)
+		}
+		if x, y, w, h := gridViewport(200, 400, 20, 10); x != 0 || y != tt.tallY || w != 200 || h != 100 {
+			t.Errorf("-align %s in a tall window: got %d,%d %dx%d, want 0,%d 200x100", tt.align, x, y, w, h, tt.tallY)
+		}
+	}
+}
+
+func TestGridViewportStretch(t *testing.T) {
+	defer func(l bool, a string) { *letterbox, *align = l, a }(*letterbox, *align)
+	*letterbox, *align = false, alignTopRight
+
+	if x, y, w, h := gridViewport(400, 200, 10, 20); x != 0 || y != 0 || w != 400 || h != 200 {
+		t.Errorf("without -letterbox: got %d,%d %dx%d, want the whole 400x200 window", x, y, w, h)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+var (
+	warmup = flag.Int("warmup", 0, "step this many generations before showing the board, skipping a random board's early die-off (generations are counted from there)")
+)
+
+// warmUp steps the board through -warmup generations without drawing or
+// printing them, growing it with -grow as it goes. It stops early if ctx is
+// done.
+func warmUp(ctx context.Context, board *life.Board, kernel *smoothKernel, growth *grower) {
+	if *warmup == 0 {
+		return
+	}
+
+	for i := 0; i < *warmup; i++ {
+		if ctx.Err() != nil {
+			logInfof("Warmup interrupted after %d generations", i)
+			return
+		}
+		if growth != nil {
+			growth.grow(board)
+		}
+		stepBoard(board, kernel)
+	}
+	logInfof("Warmed up for %d generations", *warmup)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var (
+	watchFor = flag.String("watchfor", "", "pause the first time the pattern in this file appears on its own on the board, in any rotation or reflection, and highlight it")
+)
+
+// watcher looks for the first appearance of a pattern on the board, in any
+// of its orientations. A match is the pattern's live and dead cells exactly,
+// with nothing alive in the ring of cells around it, so a glider inside a
+// bigger blob doesn't count.
+//
+// Every orientation is only tried where its first live cell lands on a live
+// cell of the board, so the cost grows with the population rather than the
+// size of the board: live cells times orientations times the pattern's area.
+type watcher struct {
+	orientations [][][]bool
+
+	// found is set once the pattern has been seen, after which it isn't
+	// looked for again until reset.
+	found bool
+}
+
+// patternMatch is where a watched pattern was found: the bottom-left corner
+// of its bounding box, and the board's cells that were alive in it.
+type patternMatch struct {
+	x, y  int
+	cells [][2]int
+}
+
+func newWatcher(pattern [][]bool) *watcher {
+	return &watcher{orientations: orientations(pattern)}
+}
+
+// reset looks for the pattern again, on a new board.
+func (w *watcher) reset() {
+	w.found = false
+}
+
+// find returns the first match of the pattern on a board of cells, which
+// wraps around its edges if wrap is set, and marks the pattern found. The
+// board is searched left to right, then bottom to top, by where each match's
+// first live cell is.
+func (w *watcher) find(cells [][]bool, wrap bool) (*patternMatch, bool) {
+	if w.found {
+		return nil, false
+	}
+
+	for x := range cells {
+		for y, alive := range cells[x] {
+			if !alive {
+				continue
+			}
+			for _, o := range w.orientations {
+				ax, ay := firstLive(o)
+				if m, ok := matchAt(cells, o, x-ax, y-ay, wrap); ok {
+					w.found = true
+					return m, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// matchAt reports whether pattern is on its own on the board with its
+// bottom-left corner at x, y. Cells past the edges wrap around if wrap is
+// set, and are dead otherwise.
+func matchAt(cells [][]bool, pattern [][]bool, x, y int, wrap bool) (*patternMatch, bool) {
+	rows, columns := len(cells), len(cells[0])
+	width, height := len(pattern), len(pattern[0])
+	if wrap && (width+2 > rows || height+2 > columns) {
+		// The ring around the pattern would overlap itself.
+		return nil, false
+	}
+
+	m := &patternMatch{x: x, y: y}
+	if wrap {
+		m.x, m.y = (x+rows)%rows, (y+columns)%columns
+	}
+	for i := -1; i <= width; i++ {
+		for j := -1; j <= height; j++ {
+			bx, by := x+i, y+j
+			if wrap {
+				bx, by = (bx+rows)%rows, (by+columns)%columns
+			}
+			var alive bool
+			if bx >= 0 && bx < rows && by >= 0 && by < columns {
+				alive = cells[bx][by]
+			}
+
+			want := i >= 0 && i < width && j >= 0 && j < height && pattern[i][j]
+			if alive != want {
+				return nil, false
+			}
+			if alive {
+				m.cells = append(m.cells, [2]int{bx, by})
+			}
+		}
+	}
+	return m, true
+}
+
+// firstLive returns the first live cell of a pattern, searching the way find
+// searches the board.
+func firstLive(pattern [][]bool) (x, y int) {
+	for x := range pattern {
+		for y, alive := range pattern[x] {
+			if alive {
+				return x, y
+			}
+		}
+	}
+	return 0, 0
+}
+
+// orientations returns the distinct rotations and reflections of a pattern,
+// the pattern itself first.
+func orientations(pattern [][]bool) [][][]bool {
+	var all [][][]bool
+	p := pattern
+	for flip := 0; flip < 2; flip++ {
+		for turn := 0; turn < 4; turn++ {
+			if !containsPattern(all, p) {
+				all = append(all, p)
+			}
+			p = rotatePattern(p)
+		}
+		p = reflectPattern(p)
+	}
+	return all
+}
+
+// rotatePattern returns a pattern turned a quarter turn counterclockwise.
+func rotatePattern(pattern [][]bool) [][]bool {
+	width, height := len(pattern), len(pattern[0])
+	rotated := make([][]bool, height)
+	for x := range rotated {
+		rotated[x] = make([]bool, width)
+		for y := range rotated[x] {
+			rotated[x][y] = pattern[y][height-1-x]
+		}
+	}
+	return rotated
+}
+
+// reflectPattern returns a pattern flipped left to right.
+func reflectPattern(pattern [][]bool) [][]bool {
+	width := len(pattern)
+	reflected := make([][]bool, width)
+	for x := range reflected {
+		reflected[x] = pattern[width-1-x]
+	}
+	return reflected
+}
+
+// containsPattern reports whether patterns includes one the same as p.
+func containsPattern(patterns [][][]bool, p [][]bool) bool {
+	for _, q := range patterns {
+		if samePattern(p, q) {
+			return true
+		}
+	}
+	return false
+}
+
+func samePattern(p, q [][]bool) bool {
+	if len(p) != len(q) || len(p[0]) != len(q[0]) {
+		return false
+	}
+	for x := range p {
+		for y := range p[x] {
+			if p[x][y] != q[x][y] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchHighlight outlines the cells of a match.
+type matchHighlight struct {
+	drawable uint32
+	vbo      uint32
+
+	vertices int32
+
+	// match is the match outlined, so a new one is noticed.
+	match *patternMatch
+}
+
+// newMatchHighlight outlines each of a match's cells on a rows by columns
+// grid, as separate lines so the outlines don't join up.
+func newMatchHighlight(m *patternMatch, rows, columns int) *matchHighlight {
+	var points []float32
+	for _, c := range m.cells {
+		corners := cellOutline(c[0], c[1], rows, columns)
+		n := len(corners) / 3
+		for i := 0; i < n; i++ {
+			next := (i + 1) % n
+			points = append(points, corners[i*3:i*3+3]...)
+			points = append(points, corners[next*3:next*3+3]...)
+		}
+	}
+
+	h := &matchHighlight{vertices: int32(len(points) / 3), match: m}
+	h.drawable, h.vbo = makeVao(points)
+	return h
+}
+
+// delete frees the highlight's GL objects.
+func (h *matchHighlight) delete() {
+	gl.DeleteVertexArrays(1, &h.drawable)
+	gl.DeleteBuffers(1, &h.vbo)
+}
+
+func (h *matchHighlight) draw(colorLocation int32) {
+	gl.Uniform4f(colorLocation, 1, 1, 0, 1)
+	gl.BindVertexArray(h.drawable)
+	gl.DrawArrays(gl.LINES, 0, h.vertices)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestOrientations(t *testing.T) {
+	tests := []struct {
+		name string
+		rle  string
+		want int
+	}{
+		{"block", "x = 2, y = 2\n2o$2o!", 1},
+		{"blinker", "x = 3, y = 1\n3o!", 2},
+		{"glider", "x = 3, y = 3\nbo$2bo$3o!", 8},
+		{"r-pentomino", "x = 3, y = 3\nb2o$2o$bo!", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := mustParseRLE(tt.rle)
+			all := orientations(pattern)
+			if len(all) != tt.want {
+				t.Errorf("got %d orientations, want %d", len(all), tt.want)
+			}
+			if !samePattern(all[0], pattern) {
+				t.Error("the first orientation isn't the pattern itself")
+			}
+
+			// Four turns come back around.
+			p := pattern
+			for i := 0; i < 4; i++ {
+				p = rotatePattern(p)
+			}
+			if !samePattern(p, pattern) {
+				t.Error("four quarter turns changed the pattern")
+			}
+		})
+	}
+}
+
+// boardOf returns a rows by columns board with the given cells alive.
+func boardOf(rows, columns int, live ...[2]int) [][]bool {
+	cells := make([][]bool, rows)
+	for x := range cells {
+		cells[x] = make([]bool, columns)
+	}
+	for _, c := range live {
+		cells[c[0]][c[1]] = true
+	}
+	return cells
+}
+
+func TestWatcherFind(t *testing.T) {
+	glider := mustParseRLE("x = 3, y = 3\nbo$2bo$3o!")
+
+	// The glider flipped top to bottom, heading up and to the right.
+	flipped := [][2]int{{4, 5}, {5, 5}, {6, 5}, {6, 6}, {5, 7}}
+
+	tests := []struct {
+		name   string
+		cells  [][]bool
+		wrap   bool
+		want   bool
+		wantXY [2]int
+	}{
+		{"glider", boardOf(12, 12, gliderCells...), false, true, [2]int{0, 0}},
+		{"flipped", boardOf(12, 12, flipped...), false, true, [2]int{4, 5}},
+		{"touching", boardOf(12, 12, append(flipped, [2]int{7, 8})...), false, false, [2]int{}},
+		{"extra cell in the box", boardOf(12, 12, append(flipped, [2]int{4, 7})...), false, false, [2]int{}},
+
+		// Shifted down and left by two, the glider wraps around the
+		// corner of the board.
+		{"wrapped", boardOf(12, 12, [2]int{11, 0}, [2]int{0, 11}, [2]int{10, 10}, [2]int{11, 10}, [2]int{0, 10}), true, true, [2]int{10, 10}},
+		{"not wrapped", boardOf(12, 12, [2]int{11, 0}, [2]int{0, 11}, [2]int{10, 10}, [2]int{11, 10}, [2]int{0, 10}), false, false, [2]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWatcher(glider)
+			m, ok := w.find(tt.cells, tt.wrap)
+			if ok != tt.want {
+				t.Fatalf("found = %t, want %t", ok, tt.want)
+			}
+			if !ok {
+				return
+			}
+			if [2]int{m.x, m.y} != tt.wantXY || len(m.cells) != 5 {
+				t.Errorf("found at %d,%d with %d cells, want %v with 5", m.x, m.y, len(m.cells), tt.wantXY)
+			}
+			if _, again := w.find(tt.cells, tt.wrap); again {
+				t.Error("found the pattern a second time")
+			}
+		})
+	}
+}
+
+func TestGameWatch(t *testing.T) {
+	glider := mustParseRLE("x = 3, y = 3\nbo$2bo$3o!")
+
+	// A glider is found on the starting board.
+	g := newGame(newTestBoard(20, 20, glider, 5, 5), nil, nil, nil, nil)
+	g.watchFor(newWatcher(glider))
+	if !g.spotted || g.match == nil || g.match.x != 5 || g.match.y != 5 {
+		t.Fatalf("glider on the starting board not found, match %+v", g.match)
+	}
+	g.Step()
+	if g.match != nil {
+		t.Error("the match is still there after stepping on from it")
+	}
+
+	// The R-pentomino sends off its first glider some way in, and it's
+	// found once it's clear of the rest.
+	g = newGame(newTestBoard(80, 80, mustParseRLE("x = 3, y = 3\nb2o$2o$bo!"), 40, 40), nil, nil, nil, nil)
+	g.board.Wrap = false
+	g.watchFor(newWatcher(glider))
+	for i := 0; i < 200 && g.match == nil; i++ {
+		g.Step()
+	}
+	if g.match == nil || g.generation < 50 {
+		t.Fatalf("R-pentomino's glider found at generation %d, match %+v", g.generation, g.match)
+	}
+	if !g.spotted || len(g.match.cells) != 5 {
+		t.Errorf("match %+v, want a glider's 5 cells, spotted", g.match)
+	}
+}
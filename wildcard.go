@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+// wildCardColor is what live wild cards are drawn in, whatever colors the
+// other cells, so it's clear where the rule is being changed.
+var wildCardColor = [4]float32{1, 0.3, 0.9, 1}
+
+var (
+	wildCards = flag.String("wildcards", "", "pattern file of wild card cells laid over the middle of the board, as -pattern is placed: each starts alive, and while it's alive the cells around it go by -wildrule")
+	wildRule  = flag.String("wildrule", "B36/S23", "the rule cells next to a live wild card of -wildcards go by")
+)
+
+// wildCardPattern is the pattern of -wildcards, and overrideRule the rule of
+// -wildrule.
+var (
+	wildCardPattern [][]bool
+	overrideRule    life.Rule
+)
+
+// placeWildCards makes every cell the pattern marks alive into a live wild
+// card, with the pattern centered on the board as stampPattern centers one.
+// The cells around them are left as they were. Wild cards that land outside
+// the grid are dropped, and the number dropped is returned.
+func placeWildCards(board *life.Board, pattern [][]bool) (clipped int) {
+	offsetX := (board.Rows() - len(pattern)) / 2
+	offsetY := (board.Columns() - len(pattern[0])) / 2
+
+	for px := range pattern {
+		for py, alive := range pattern[px] {
+			if !alive {
+				continue
+			}
+
+			x, y := offsetX+px, offsetY+py
+			if x < 0 || x >= board.Rows() || y < 0 || y >= board.Columns() {
+				clipped++
+				continue
+			}
+			c := board.Cells[x][y]
+			c.Set(true)
+			c.SetRuleOverride(true)
+		}
+	}
+	return clipped
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+func TestPlaceWildCards(t *testing.T) {
+	b := life.NewBoard(5, 5, conway)
+	b.Cells[0][0].Set(true)
+	pattern := [][]bool{{true, false}, {false, true}}
+
+	if clipped := placeWildCards(b, pattern); clipped != 0 {
+		t.Errorf("placing on a 5x5 board clipped %d wild cards, want 0", clipped)
+	}
+	for x := range b.Cells {
+		for y, c := range b.Cells[x] {
+			wild := (x == 1 && y == 1) || (x == 2 && y == 2)
+			if c.RuleOverride() != wild {
+				t.Errorf("cell %d,%d wild = %v, want %v", x, y, c.RuleOverride(), wild)
+			}
+			if wild && !c.Alive() {
+				t.Errorf("wild card %d,%d isn't alive", x, y)
+			}
+		}
+	}
+	if !b.Alive(0, 0) {
+		t.Error("placing wild cards cleared a cell of the board")
+	}
+
+	if clipped := placeWildCards(life.NewBoard(1, 1, conway), pattern); clipped != 1 {
+		t.Errorf("placing on a 1x1 board clipped %d wild cards, want 1", clipped)
+	}
+}
+
+func TestDrawColorWildCard(t *testing.T) {
+	b := life.NewBoard(2, 1, conway)
+	plain, wild := b.Cells[0][0], b.Cells[1][0]
+	plain.Color, wild.Color = [4]float32{0, 1, 0, 1}, [4]float32{0, 1, 0, 1}
+	plain.Set(true)
+	wild.Set(true)
+	wild.SetRuleOverride(true)
+
+	if color, _ := drawColor(wild); color != wildCardColor {
+		t.Errorf("live wild card drawn in %v, want %v", color, wildCardColor)
+	}
+	if color, _ := drawColor(plain); color != plain.Color {
+		t.Errorf("ordinary cell drawn in %v, want its own %v", color, plain.Color)
+	}
+	wild.Set(false)
+	if _, ok := drawColor(wild); ok {
+		t.Error("dead wild card was drawn")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aculler/conway-gol/life"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// zoneLineFade is how far the zone boundaries are faded from white towards
+// the background, so they show without standing out like grid lines.
+const zoneLineFade = 0.75
+
+var (
+	zoneRules = flag.String("zonerules", "", "semicolon-separated zones of the board with rules of their own, each x0,y0,x1,y1:rule for the cells from x0,y0 up to but not including x1,y1, such as 0,0,25,50:B3/S23;25,0,50,50:B36/S23 (the first zone a cell is in wins, and -rule covers the rest)")
+)
+
+// parseZoneRules parses -zonerules into zones of a rows by columns board.
+// Each zone has to lie on the board and have cells in it.
+func parseZoneRules(s string, rows, columns int) ([]life.Zone, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var zones []life.Zone
+	for _, field := range strings.Split(s, ";") {
+		field = strings.TrimSpace(field)
+		rect, rule, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid -zonerules zone %q: want x0,y0,x1,y1:rule", field)
+		}
+
+		corners := strings.Split(rect, ",")
+		if len(corners) != 4 {
+			return nil, fmt.Errorf("invalid -zonerules zone %q: want x0,y0,x1,y1:rule", field)
+		}
+		var n [4]int
+		for i, c := range corners {
+			var err error
+			if n[i], err = strconv.Atoi(strings.TrimSpace(c)); err != nil {
+				return nil, fmt.Errorf("invalid -zonerules zone %q: %v", field, err)
+			}
+		}
+		if n[0] < 0 || n[1] < 0 || n[2] > rows || n[3] > columns || n[0] >= n[2] || n[1] >= n[3] {
+			return nil, fmt.Errorf("invalid -zonerules zone %q: not a rectangle of cells on the %dx%d board", field, rows, columns)
+		}
+
+		r, err := life.ParseRule(strings.TrimSpace(rule))
+		if err != nil {
+			return nil, fmt.Errorf("invalid -zonerules zone %q: %v", field, err)
+		}
+		zones = append(zones, life.Zone{X0: n[0], Y0: n[1], X1: n[2], Y1: n[3], Rule: r})
+	}
+	return zones, nil
+}
+
+// zoneLines are the faint outlines of a board's zones.
+type zoneLines struct {
+	drawable uint32
+	vbo      uint32
+
+	vertices int32
+}
+
+func newZoneLines(zones []life.Zone, rows, columns int) *zoneLines {
+	// Each zone is outlined along its cells' outer edges, in normalized
+	// device coordinates, where cellPoints puts them.
+	var points []float32
+	for _, z := range zones {
+		left, right := float32(z.X0)*2/float32(rows)-1, float32(z.X1)*2/float32(rows)-1
+		bottom, top := float32(z.Y0)*2/float32(columns)-1, float32(z.Y1)*2/float32(columns)-1
+		points = append(points,
+			left, bottom, 0, right, bottom, 0,
+			right, bottom, 0, right, top, 0,
+			right, top, 0, left, top, 0,
+			left, top, 0, left, bottom, 0,
+		)
+	}
+
+	l := &zoneLines{vertices: int32(len(points) / 3)}
+	l.drawable, l.vbo = makeVao(points)
+	return l
+}
+
+// delete frees the lines' GL objects.
+func (l *zoneLines) delete() {
+	gl.DeleteVertexArrays(1, &l.drawable)
+	gl.DeleteBuffers(1, &l.vbo)
+}
+
+func (l *zoneLines) draw(colorLocation int32) {
+	var c [3]float32
+	for i := range c {
+		c[i] = 1 - zoneLineFade*(1-backgroundColor[i])
+	}
+	gl.Uniform4f(colorLocation, c[0], c[1], c[2], 1)
+	gl.BindVertexArray(l.drawable)
+	gl.DrawArrays(gl.LINES, 0, l.vertices)
+}
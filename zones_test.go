@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aculler/conway-gol/life"
+)
+
+func TestParseZoneRules(t *testing.T) {
+	highLife, _ := life.ParseRule("B36/S23")
+
+	zones, err := parseZoneRules("0,0,25,50:B3/S23; 25, 0, 50, 50 : B36/S23", 50, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []life.Zone{
+		{X0: 0, Y0: 0, X1: 25, Y1: 50, Rule: conway},
+		{X0: 25, Y0: 0, X1: 50, Y1: 50, Rule: highLife},
+	}
+	if len(zones) != len(want) {
+		t.Fatalf("got %d zones, want %d", len(zones), len(want))
+	}
+	for i := range want {
+		if zones[i] != want[i] {
+			t.Errorf("zone %d = %+v, want %+v", i, zones[i], want[i])
+		}
+	}
+
+	if zones, err := parseZoneRules("", 50, 50); zones != nil || err != nil {
+		t.Errorf("parseZoneRules(\"\") = %v, %v, want no zones", zones, err)
+	}
+}
+
+func TestParseZoneRulesInvalid(t *testing.T) {
+	for _, s := range []string{
+		"0,0,25,50",
+		"0,0,25:B3/S23",
+		"0,0,25,fifty:B3/S23",
+		"0,0,25,50:B9/S23",
+		"0,0,25,51:B3/S23",
+		"-1,0,25,50:B3/S23",
+		"10,0,10,50:B3/S23",
+		"20,0,10,50:B3/S23",
+		"0,0,25,50:B3/S23;",
+	} {
+		if _, err := parseZoneRules(s, 50, 50); err == nil {
+			t.Errorf("parseZoneRules(%q) succeeded, want an error", s)
+		}
+	}
+}